@@ -0,0 +1,293 @@
+// Package admin exposes breakers over HTTP and gRPC for inspection and control during incidents, e.g. by grpcbreakerctl.
+//
+// The handler serves:
+//
+//	GET  /breakers           the JSON array of all breakers, optionally filtered by the type and name query parameters
+//	GET  /events             newline-delimited JSON events as they're published, filtered likewise, until disconnected
+//	POST /override           a JSON Override, forcing a breaker into a state
+//
+// where type is a BreakerType such as "service" and name is a prefix of breaker names.
+//
+// Server offers the same over gRPC as the grpcbreaker.admin.v1.BreakerAdmin service, for tooling in other languages.
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+// Breaker is the JSON representation of a grpcbreaker.Snapshot
+type Breaker struct {
+	Key
+	State       string     `json:"state"`
+	Gen         uint64     `json:"gen"`
+	Fails       int        `json:"fails"`
+	Passes      int        `json:"passes"`
+	Ignored     int        `json:"ignored"`
+	FailScore   float64    `json:"failScore"`
+	LastFail    *time.Time `json:"lastFail,omitempty"`
+	LastUsed    *time.Time `json:"lastUsed,omitempty"`
+	ResetMoment *time.Time `json:"resetMoment,omitempty"`
+	Transitions []Event    `json:"transitions,omitempty"` // the most recent, oldest first
+	Causes      []Cause    `json:"causes,omitempty"`      // the most recent failures in the current state, oldest first
+}
+
+// Cause is the JSON representation of a grpcbreaker.Cause
+type Cause struct {
+	Method  string    `json:"method"`
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Key is the JSON representation of a grpcbreaker.Key, with the type given by name
+type Key struct {
+	Type      string `json:"type"`
+	Name      string `json:"name,omitempty"`
+	Partition string `json:"partition,omitempty"`
+}
+
+// Event is the JSON representation of a grpcbreaker.Event
+type Event struct {
+	Kind string `json:"kind"` // "state", "shed" or "evicted"
+	Key
+	Time time.Time `json:"time"`
+	// Old and New are set for state events, and State for shed events
+	Old       string  `json:"old,omitempty"`
+	New       string  `json:"new,omitempty"`
+	State     string  `json:"state,omitempty"`
+	Gen       uint64  `json:"gen"`
+	Fails     int     `json:"fails,omitempty"`
+	Passes    int     `json:"passes,omitempty"`
+	Ignored   int     `json:"ignored,omitempty"`
+	FailScore float64 `json:"failScore,omitempty"`
+	Causes    []Cause `json:"causes,omitempty"` // for transitions, the most recent failures of the old state
+}
+
+// Override is the body of a request to force a breaker into a state: "open", "closed" or "halfopen"
+type Override struct {
+	Key
+	State string `json:"state"`
+}
+
+// NewHandler returns a handler serving the breakers
+func NewHandler(b *grpcbreaker.Breaker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/breakers", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		match, err := filter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		breakers := []Breaker{}
+		for _, s := range b.Snapshot() {
+			if match(s.Key) {
+				breakers = append(breakers, FromSnapshot(s))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(breakers)
+	})
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		match, err := filter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		events := b.Subscribe(r.Context(), 100)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		enc := json.NewEncoder(w)
+		for ev := range events {
+			e, ok := FromEvent(ev)
+			if !ok || !match(e.Key.key()) {
+				continue
+			}
+			if enc.Encode(e) != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	})
+
+	mux.HandleFunc("/override", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var o Override
+		if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		key, err := o.Key.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		state, err := ParseState(o.State)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch err := b.Override(key, state); {
+		case errors.Is(err, grpcbreaker.ErrNoBreaker):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	return mux
+}
+
+// filter returns a function matching keys against the request's type and name query parameters
+func filter(r *http.Request) (func(grpcbreaker.Key) bool, error) {
+	q := r.URL.Query()
+	prefix := q.Get("name")
+	if q.Get("type") == "" {
+		return func(k grpcbreaker.Key) bool { return strings.HasPrefix(k.Name, prefix) }, nil
+	}
+
+	t, err := ParseType(q.Get("type"))
+	if err != nil {
+		return nil, err
+	}
+	return func(k grpcbreaker.Key) bool { return k.Type == t && strings.HasPrefix(k.Name, prefix) }, nil
+}
+
+// FromSnapshot converts a snapshot to its JSON representation
+func FromSnapshot(s grpcbreaker.Snapshot) Breaker {
+	b := Breaker{
+		Key:         FromKey(s.Key),
+		State:       s.State.State().String(),
+		Gen:         s.State.Gen(),
+		Fails:       s.Fails,
+		Passes:      s.Passes,
+		Ignored:     s.Ignored,
+		FailScore:   s.FailScore,
+		LastFail:    timePtr(s.LastFail),
+		LastUsed:    timePtr(s.LastUsed),
+		ResetMoment: timePtr(s.ResetMoment),
+	}
+	for _, t := range s.Transitions {
+		ev, _ := FromEvent(t)
+		b.Transitions = append(b.Transitions, ev)
+	}
+	b.Causes = fromCauses(s.Causes)
+	return b
+}
+
+func fromCauses(causes []grpcbreaker.Cause) []Cause {
+	var cs []Cause
+	for _, c := range causes {
+		cs = append(cs, Cause{Method: c.Method, Code: c.Code.String(), Message: c.Message, Time: c.Time})
+	}
+	return cs
+}
+
+// FromEvent converts an event to its JSON representation, returning false for unknown events
+func FromEvent(ev grpcbreaker.Event) (Event, bool) {
+	switch e := ev.(type) {
+	case grpcbreaker.StateEvent:
+		return Event{
+			Kind:      "state",
+			Key:       FromKey(e.Key),
+			Time:      e.Published,
+			Old:       e.Old.State().String(),
+			New:       e.New.State().String(),
+			Gen:       e.New.Gen(),
+			Fails:     e.Fails,
+			Passes:    e.Passes,
+			Ignored:   e.Ignored,
+			FailScore: e.FailScore,
+			Causes:    fromCauses(e.Causes),
+		}, true
+	case grpcbreaker.ShedEvent:
+		return Event{Kind: "shed", Key: FromKey(e.Key), Time: e.Published, State: e.State.State().String(), Gen: e.State.Gen()}, true
+	case grpcbreaker.EvictedEvent:
+		return Event{Kind: "evicted", Key: FromKey(e.Key), Time: e.Published}, true
+	}
+	return Event{}, false
+}
+
+// FromKey converts a key to its JSON representation
+func FromKey(k grpcbreaker.Key) Key {
+	return Key{Type: typeNames[k.Type], Name: k.Name, Partition: k.Partition}
+}
+
+// Parse converts the key back from its JSON representation
+func (k Key) Parse() (grpcbreaker.Key, error) {
+	t, err := ParseType(k.Type)
+	return grpcbreaker.Key{Type: t, Name: k.Name, Partition: k.Partition}, err
+}
+
+// key converts a key known to be valid
+func (k Key) key() grpcbreaker.Key {
+	key, _ := k.Parse()
+	return key
+}
+
+var typeNames = map[grpcbreaker.BreakerType]string{
+	grpcbreaker.BreakerGlobal:   "global",
+	grpcbreaker.BreakerService:  "service",
+	grpcbreaker.BreakerMethod:   "method",
+	grpcbreaker.BreakerCallSite: "callsite",
+	grpcbreaker.BreakerCircuit:  "circuit",
+	grpcbreaker.BreakerHost:     "host",
+	grpcbreaker.BreakerPath:     "path",
+	grpcbreaker.BreakerPackage:  "package",
+}
+
+// ParseType parses the name of a BreakerType, e.g. "service"
+func ParseType(name string) (grpcbreaker.BreakerType, error) {
+	for t, n := range typeNames {
+		if strings.EqualFold(name, n) || strings.EqualFold(name, t.String()) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown breaker type %q", name)
+}
+
+// ParseState parses the name of a State, e.g. "open" or "halfopen"
+func ParseState(name string) (grpcbreaker.State, error) {
+	for _, s := range []grpcbreaker.State{grpcbreaker.Closed, grpcbreaker.HalfOpen, grpcbreaker.Open} {
+		if strings.EqualFold(strings.ReplaceAll(name, "-", ""), s.String()) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown state %q", name)
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
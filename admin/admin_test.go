@@ -0,0 +1,108 @@
+package admin_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/admin"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
+)
+
+func TestHandler(t *testing.T) {
+	h := grpcbreakertest.NewBreaker(
+		t,
+		grpcbreaker.Global(grpcbreaker.ResetTimeout(time.Minute)),
+		grpcbreaker.Service("/pkg.Svc"),
+		grpcbreaker.Method("/pkg.Svc/Get"),
+	)
+	srv := httptest.NewServer(admin.NewHandler(h.Breaker))
+	t.Cleanup(srv.Close)
+
+	list := func(t *testing.T, query string) []admin.Breaker {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "/breakers" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var breakers []admin.Breaker
+		if err := json.NewDecoder(resp.Body).Decode(&breakers); err != nil {
+			t.Fatal(err)
+		}
+		return breakers
+	}
+
+	override := func(t *testing.T, o admin.Override) int {
+		t.Helper()
+		b, _ := json.Marshal(o)
+		resp, err := http.Post(srv.URL+"/override", "application/json", bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("lists", func(t *testing.T) {
+		if got := list(t, ""); len(got) != 3 || got[0].Type != "global" || got[2].Name != "/pkg.Svc/Get" {
+			t.Fatalf("unexpected breakers %+v", got)
+		}
+		if got := list(t, "?type=method&name=/pkg"); len(got) != 1 || got[0].State != "Closed" {
+			t.Fatalf("unexpected breakers %+v", got)
+		}
+	})
+
+	t.Run("overrides and streams events", func(t *testing.T) {
+		ctx, cncl := context.WithCancel(context.Background())
+		defer cncl()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events?type=service", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if c := override(t, admin.Override{Key: admin.Key{Type: "method", Name: "/pkg.Svc/Get"}, State: "open"}); c != http.StatusNoContent {
+			t.Fatalf("expected %v but got %v", http.StatusNoContent, c)
+		}
+		if c := override(t, admin.Override{Key: admin.Key{Type: "service", Name: "/pkg.Svc"}, State: "open"}); c != http.StatusNoContent {
+			t.Fatalf("expected %v but got %v", http.StatusNoContent, c)
+		}
+
+		// only the service's transition is streamed
+		var ev admin.Event
+		if err := json.NewDecoder(bufio.NewReader(resp.Body)).Decode(&ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.Kind != "state" || ev.Name != "/pkg.Svc" || ev.Old != "Closed" || ev.New != "Open" {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+
+		if got := list(t, "?type=service"); got[0].State != "Open" || got[0].ResetMoment == nil {
+			t.Fatalf("unexpected breakers %+v", got)
+		}
+	})
+
+	t.Run("rejects bad overrides", func(t *testing.T) {
+		for _, tc := range []struct {
+			o    admin.Override
+			code int
+		}{
+			{admin.Override{Key: admin.Key{Type: "nope"}, State: "open"}, http.StatusBadRequest},
+			{admin.Override{Key: admin.Key{Type: "global"}, State: "nope"}, http.StatusBadRequest},
+			{admin.Override{Key: admin.Key{Type: "method", Name: "/pkg.Svc/List"}, State: "open"}, http.StatusNotFound},
+			{admin.Override{Key: admin.Key{Type: "global"}, State: "halfopen"}, http.StatusConflict},
+		} {
+			if c := override(t, tc.o); c != tc.code {
+				t.Errorf("%+v: expected %v but got %v", tc.o, tc.code, c)
+			}
+		}
+	})
+}
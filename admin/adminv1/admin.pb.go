@@ -0,0 +1,1567 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.15.5
+// source: grpcbreaker/admin/v1/admin.proto
+
+// BreakerAdmin exposes the breakers of a process to tooling in any language, much as channelz and health do for
+// channels and servers.
+
+package adminv1
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// BreakerType corresponds to grpcbreaker.BreakerType, offset by one so that an unset type isn't mistaken for global
+type BreakerType int32
+
+const (
+	BreakerType_BREAKER_TYPE_UNSPECIFIED BreakerType = 0
+	BreakerType_BREAKER_TYPE_GLOBAL      BreakerType = 1
+	BreakerType_BREAKER_TYPE_SERVICE     BreakerType = 2
+	BreakerType_BREAKER_TYPE_METHOD      BreakerType = 3
+	BreakerType_BREAKER_TYPE_CALL_SITE   BreakerType = 4
+	BreakerType_BREAKER_TYPE_CIRCUIT     BreakerType = 5
+	BreakerType_BREAKER_TYPE_HOST        BreakerType = 6
+	BreakerType_BREAKER_TYPE_PATH        BreakerType = 7
+	BreakerType_BREAKER_TYPE_PACKAGE     BreakerType = 8
+)
+
+// Enum value maps for BreakerType.
+var (
+	BreakerType_name = map[int32]string{
+		0: "BREAKER_TYPE_UNSPECIFIED",
+		1: "BREAKER_TYPE_GLOBAL",
+		2: "BREAKER_TYPE_SERVICE",
+		3: "BREAKER_TYPE_METHOD",
+		4: "BREAKER_TYPE_CALL_SITE",
+		5: "BREAKER_TYPE_CIRCUIT",
+		6: "BREAKER_TYPE_HOST",
+		7: "BREAKER_TYPE_PATH",
+		8: "BREAKER_TYPE_PACKAGE",
+	}
+	BreakerType_value = map[string]int32{
+		"BREAKER_TYPE_UNSPECIFIED": 0,
+		"BREAKER_TYPE_GLOBAL":      1,
+		"BREAKER_TYPE_SERVICE":     2,
+		"BREAKER_TYPE_METHOD":      3,
+		"BREAKER_TYPE_CALL_SITE":   4,
+		"BREAKER_TYPE_CIRCUIT":     5,
+		"BREAKER_TYPE_HOST":        6,
+		"BREAKER_TYPE_PATH":        7,
+		"BREAKER_TYPE_PACKAGE":     8,
+	}
+)
+
+func (x BreakerType) Enum() *BreakerType {
+	p := new(BreakerType)
+	*p = x
+	return p
+}
+
+func (x BreakerType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BreakerType) Descriptor() protoreflect.EnumDescriptor {
+	return file_grpcbreaker_admin_v1_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (BreakerType) Type() protoreflect.EnumType {
+	return &file_grpcbreaker_admin_v1_admin_proto_enumTypes[0]
+}
+
+func (x BreakerType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BreakerType.Descriptor instead.
+func (BreakerType) EnumDescriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+// State corresponds to grpcbreaker.State
+type State int32
+
+const (
+	State_STATE_UNKNOWN   State = 0
+	State_STATE_CLOSED    State = 1
+	State_STATE_HALF_OPEN State = 2
+	State_STATE_OPEN      State = 3
+)
+
+// Enum value maps for State.
+var (
+	State_name = map[int32]string{
+		0: "STATE_UNKNOWN",
+		1: "STATE_CLOSED",
+		2: "STATE_HALF_OPEN",
+		3: "STATE_OPEN",
+	}
+	State_value = map[string]int32{
+		"STATE_UNKNOWN":   0,
+		"STATE_CLOSED":    1,
+		"STATE_HALF_OPEN": 2,
+		"STATE_OPEN":      3,
+	}
+)
+
+func (x State) Enum() *State {
+	p := new(State)
+	*p = x
+	return p
+}
+
+func (x State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (State) Descriptor() protoreflect.EnumDescriptor {
+	return file_grpcbreaker_admin_v1_admin_proto_enumTypes[1].Descriptor()
+}
+
+func (State) Type() protoreflect.EnumType {
+	return &file_grpcbreaker_admin_v1_admin_proto_enumTypes[1]
+}
+
+func (x State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use State.Descriptor instead.
+func (State) EnumDescriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+type Key struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      BreakerType `protobuf:"varint,1,opt,name=type,proto3,enum=grpcbreaker.admin.v1.BreakerType" json:"type,omitempty"`
+	Name      string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Partition string      `protobuf:"bytes,3,opt,name=partition,proto3" json:"partition,omitempty"`
+}
+
+func (x *Key) Reset() {
+	*x = Key{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Key) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Key) ProtoMessage() {}
+
+func (x *Key) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Key.ProtoReflect.Descriptor instead.
+func (*Key) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Key) GetType() BreakerType {
+	if x != nil {
+		return x.Type
+	}
+	return BreakerType_BREAKER_TYPE_UNSPECIFIED
+}
+
+func (x *Key) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Key) GetPartition() string {
+	if x != nil {
+		return x.Partition
+	}
+	return ""
+}
+
+type Breaker struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key         *Key                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	State       State                `protobuf:"varint,2,opt,name=state,proto3,enum=grpcbreaker.admin.v1.State" json:"state,omitempty"`
+	Gen         uint64               `protobuf:"varint,3,opt,name=gen,proto3" json:"gen,omitempty"`
+	Fails       int64                `protobuf:"varint,4,opt,name=fails,proto3" json:"fails,omitempty"`
+	Passes      int64                `protobuf:"varint,5,opt,name=passes,proto3" json:"passes,omitempty"`
+	Ignored     int64                `protobuf:"varint,6,opt,name=ignored,proto3" json:"ignored,omitempty"`
+	FailScore   float64              `protobuf:"fixed64,7,opt,name=fail_score,json=failScore,proto3" json:"fail_score,omitempty"`
+	LastFail    *timestamp.Timestamp `protobuf:"bytes,8,opt,name=last_fail,json=lastFail,proto3" json:"last_fail,omitempty"`
+	LastUsed    *timestamp.Timestamp `protobuf:"bytes,9,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	ResetMoment *timestamp.Timestamp `protobuf:"bytes,10,opt,name=reset_moment,json=resetMoment,proto3" json:"reset_moment,omitempty"`
+	// transitions are the breaker's most recent transitions, oldest first
+	Transitions []*StateEvent `protobuf:"bytes,11,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	// causes are the most recent failures in the current state, oldest first
+	Causes []*Cause `protobuf:"bytes,12,rep,name=causes,proto3" json:"causes,omitempty"`
+}
+
+func (x *Breaker) Reset() {
+	*x = Breaker{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Breaker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breaker) ProtoMessage() {}
+
+func (x *Breaker) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breaker.ProtoReflect.Descriptor instead.
+func (*Breaker) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Breaker) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *Breaker) GetState() State {
+	if x != nil {
+		return x.State
+	}
+	return State_STATE_UNKNOWN
+}
+
+func (x *Breaker) GetGen() uint64 {
+	if x != nil {
+		return x.Gen
+	}
+	return 0
+}
+
+func (x *Breaker) GetFails() int64 {
+	if x != nil {
+		return x.Fails
+	}
+	return 0
+}
+
+func (x *Breaker) GetPasses() int64 {
+	if x != nil {
+		return x.Passes
+	}
+	return 0
+}
+
+func (x *Breaker) GetIgnored() int64 {
+	if x != nil {
+		return x.Ignored
+	}
+	return 0
+}
+
+func (x *Breaker) GetFailScore() float64 {
+	if x != nil {
+		return x.FailScore
+	}
+	return 0
+}
+
+func (x *Breaker) GetLastFail() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastFail
+	}
+	return nil
+}
+
+func (x *Breaker) GetLastUsed() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastUsed
+	}
+	return nil
+}
+
+func (x *Breaker) GetResetMoment() *timestamp.Timestamp {
+	if x != nil {
+		return x.ResetMoment
+	}
+	return nil
+}
+
+func (x *Breaker) GetTransitions() []*StateEvent {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+func (x *Breaker) GetCauses() []*Cause {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
+// Cause is a failure which counted toward tripping a breaker
+type Cause struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// code is the status code, as in google.rpc.Code
+	Code    int32                `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message string               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Time    *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *Cause) Reset() {
+	*x = Cause{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Cause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cause) ProtoMessage() {}
+
+func (x *Cause) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cause.ProtoReflect.Descriptor instead.
+func (*Cause) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Cause) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Cause) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Cause) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Cause) GetTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// Filter selects breakers by type and name; empty fields match everything
+type Filter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Types      []BreakerType `protobuf:"varint,1,rep,packed,name=types,proto3,enum=grpcbreaker.admin.v1.BreakerType" json:"types,omitempty"`
+	NamePrefix string        `protobuf:"bytes,2,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+}
+
+func (x *Filter) Reset() {
+	*x = Filter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Filter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filter) ProtoMessage() {}
+
+func (x *Filter) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filter.ProtoReflect.Descriptor instead.
+func (*Filter) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Filter) GetTypes() []BreakerType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *Filter) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *Filter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Breakers []*Breaker `protobuf:"bytes,1,rep,name=breakers,proto3" json:"breakers,omitempty"`
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListResponse) GetBreakers() []*Breaker {
+	if x != nil {
+		return x.Breakers
+	}
+	return nil
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key *Key `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetRequest) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *Filter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*Event_State
+	//	*Event_Shed
+	//	*Event_Evicted
+	Event isEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (m *Event) GetEvent() isEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *Event) GetState() *StateEvent {
+	if x, ok := x.GetEvent().(*Event_State); ok {
+		return x.State
+	}
+	return nil
+}
+
+func (x *Event) GetShed() *ShedEvent {
+	if x, ok := x.GetEvent().(*Event_Shed); ok {
+		return x.Shed
+	}
+	return nil
+}
+
+func (x *Event) GetEvicted() *EvictedEvent {
+	if x, ok := x.GetEvent().(*Event_Evicted); ok {
+		return x.Evicted
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_State struct {
+	State *StateEvent `protobuf:"bytes,1,opt,name=state,proto3,oneof"`
+}
+
+type Event_Shed struct {
+	Shed *ShedEvent `protobuf:"bytes,2,opt,name=shed,proto3,oneof"`
+}
+
+type Event_Evicted struct {
+	Evicted *EvictedEvent `protobuf:"bytes,3,opt,name=evicted,proto3,oneof"`
+}
+
+func (*Event_State) isEvent_Event() {}
+
+func (*Event_Shed) isEvent_Event() {}
+
+func (*Event_Evicted) isEvent_Event() {}
+
+type StateEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key         *Key                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Old         State                `protobuf:"varint,2,opt,name=old,proto3,enum=grpcbreaker.admin.v1.State" json:"old,omitempty"`
+	New         State                `protobuf:"varint,3,opt,name=new,proto3,enum=grpcbreaker.admin.v1.State" json:"new,omitempty"`
+	OldGen      uint64               `protobuf:"varint,4,opt,name=old_gen,json=oldGen,proto3" json:"old_gen,omitempty"`
+	NewGen      uint64               `protobuf:"varint,5,opt,name=new_gen,json=newGen,proto3" json:"new_gen,omitempty"`
+	Published   *timestamp.Timestamp `protobuf:"bytes,6,opt,name=published,proto3" json:"published,omitempty"`
+	LastFail    *timestamp.Timestamp `protobuf:"bytes,7,opt,name=last_fail,json=lastFail,proto3" json:"last_fail,omitempty"`
+	ResetMoment *timestamp.Timestamp `protobuf:"bytes,8,opt,name=reset_moment,json=resetMoment,proto3" json:"reset_moment,omitempty"`
+	Fails       int64                `protobuf:"varint,9,opt,name=fails,proto3" json:"fails,omitempty"`
+	Passes      int64                `protobuf:"varint,10,opt,name=passes,proto3" json:"passes,omitempty"`
+	Ignored     int64                `protobuf:"varint,11,opt,name=ignored,proto3" json:"ignored,omitempty"`
+	FailScore   float64              `protobuf:"fixed64,12,opt,name=fail_score,json=failScore,proto3" json:"fail_score,omitempty"`
+	// causes are, for transitions, the most recent failures of the old state
+	Causes []*Cause `protobuf:"bytes,13,rep,name=causes,proto3" json:"causes,omitempty"`
+}
+
+func (x *StateEvent) Reset() {
+	*x = StateEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateEvent) ProtoMessage() {}
+
+func (x *StateEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateEvent.ProtoReflect.Descriptor instead.
+func (*StateEvent) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StateEvent) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *StateEvent) GetOld() State {
+	if x != nil {
+		return x.Old
+	}
+	return State_STATE_UNKNOWN
+}
+
+func (x *StateEvent) GetNew() State {
+	if x != nil {
+		return x.New
+	}
+	return State_STATE_UNKNOWN
+}
+
+func (x *StateEvent) GetOldGen() uint64 {
+	if x != nil {
+		return x.OldGen
+	}
+	return 0
+}
+
+func (x *StateEvent) GetNewGen() uint64 {
+	if x != nil {
+		return x.NewGen
+	}
+	return 0
+}
+
+func (x *StateEvent) GetPublished() *timestamp.Timestamp {
+	if x != nil {
+		return x.Published
+	}
+	return nil
+}
+
+func (x *StateEvent) GetLastFail() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastFail
+	}
+	return nil
+}
+
+func (x *StateEvent) GetResetMoment() *timestamp.Timestamp {
+	if x != nil {
+		return x.ResetMoment
+	}
+	return nil
+}
+
+func (x *StateEvent) GetFails() int64 {
+	if x != nil {
+		return x.Fails
+	}
+	return 0
+}
+
+func (x *StateEvent) GetPasses() int64 {
+	if x != nil {
+		return x.Passes
+	}
+	return 0
+}
+
+func (x *StateEvent) GetIgnored() int64 {
+	if x != nil {
+		return x.Ignored
+	}
+	return 0
+}
+
+func (x *StateEvent) GetFailScore() float64 {
+	if x != nil {
+		return x.FailScore
+	}
+	return 0
+}
+
+func (x *StateEvent) GetCauses() []*Cause {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
+type ShedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key       *Key                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	State     State                `protobuf:"varint,2,opt,name=state,proto3,enum=grpcbreaker.admin.v1.State" json:"state,omitempty"`
+	Gen       uint64               `protobuf:"varint,3,opt,name=gen,proto3" json:"gen,omitempty"`
+	Published *timestamp.Timestamp `protobuf:"bytes,4,opt,name=published,proto3" json:"published,omitempty"`
+}
+
+func (x *ShedEvent) Reset() {
+	*x = ShedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShedEvent) ProtoMessage() {}
+
+func (x *ShedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShedEvent.ProtoReflect.Descriptor instead.
+func (*ShedEvent) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ShedEvent) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *ShedEvent) GetState() State {
+	if x != nil {
+		return x.State
+	}
+	return State_STATE_UNKNOWN
+}
+
+func (x *ShedEvent) GetGen() uint64 {
+	if x != nil {
+		return x.Gen
+	}
+	return 0
+}
+
+func (x *ShedEvent) GetPublished() *timestamp.Timestamp {
+	if x != nil {
+		return x.Published
+	}
+	return nil
+}
+
+type EvictedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key       *Key                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Published *timestamp.Timestamp `protobuf:"bytes,2,opt,name=published,proto3" json:"published,omitempty"`
+	LastUsed  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+}
+
+func (x *EvictedEvent) Reset() {
+	*x = EvictedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvictedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvictedEvent) ProtoMessage() {}
+
+func (x *EvictedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvictedEvent.ProtoReflect.Descriptor instead.
+func (*EvictedEvent) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EvictedEvent) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *EvictedEvent) GetPublished() *timestamp.Timestamp {
+	if x != nil {
+		return x.Published
+	}
+	return nil
+}
+
+func (x *EvictedEvent) GetLastUsed() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastUsed
+	}
+	return nil
+}
+
+type OverrideRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   *Key  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	State State `protobuf:"varint,2,opt,name=state,proto3,enum=grpcbreaker.admin.v1.State" json:"state,omitempty"`
+}
+
+func (x *OverrideRequest) Reset() {
+	*x = OverrideRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverrideRequest) ProtoMessage() {}
+
+func (x *OverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_admin_v1_admin_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverrideRequest.ProtoReflect.Descriptor instead.
+func (*OverrideRequest) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OverrideRequest) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *OverrideRequest) GetState() State {
+	if x != nil {
+		return x.State
+	}
+	return State_STATE_UNKNOWN
+}
+
+var File_grpcbreaker_admin_v1_admin_proto protoreflect.FileDescriptor
+
+var file_grpcbreaker_admin_v1_admin_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x14, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6e, 0x0a, 0x03, 0x4b, 0x65, 0x79,
+	0x12, 0x35, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d,
+	0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x8c, 0x04, 0x0a, 0x07, 0x42, 0x72,
+	0x65, 0x61, 0x6b, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72,
+	0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x31, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x03, 0x67, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x61, 0x69, 0x6c, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x66, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x70,
+	0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x66, 0x61, 0x69, 0x6c, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x37,
+	0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6c,
+	0x61, 0x73, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x12, 0x37, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f,
+	0x75, 0x73, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x73, 0x65, 0x64,
+	0x12, 0x3d, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x6d, 0x6f, 0x6d, 0x65, 0x6e, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x6f, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x42, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0b,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b,
+	0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x33, 0x0a, 0x06, 0x63, 0x61, 0x75, 0x73, 0x65, 0x73, 0x18, 0x0c, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x75, 0x73, 0x65,
+	0x52, 0x06, 0x63, 0x61, 0x75, 0x73, 0x65, 0x73, 0x22, 0x7d, 0x0a, 0x05, 0x43, 0x61, 0x75, 0x73,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x62, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x12, 0x37, 0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e,
+	0x32, 0x21, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61,
+	0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x61,
+	0x6d, 0x65, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x43, 0x0a, 0x0b, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x66, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x22, 0x49, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x39, 0x0a, 0x08, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72,
+	0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x52, 0x08, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x22, 0x39, 0x0a, 0x0a, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65,
+	0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65,
+	0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x44, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65,
+	0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0xc1, 0x01, 0x0a,
+	0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x38, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61,
+	0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x35, 0x0a, 0x04, 0x73, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d,
+	0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
+	0x00, 0x52, 0x04, 0x73, 0x68, 0x65, 0x64, 0x12, 0x3e, 0x0a, 0x07, 0x65, 0x76, 0x69, 0x63, 0x74,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62,
+	0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x69, 0x63, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x07,
+	0x65, 0x76, 0x69, 0x63, 0x74, 0x65, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x22, 0x97, 0x04, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x2b, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x03,
+	0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x6f, 0x6c, 0x64, 0x12, 0x2d, 0x0a, 0x03, 0x6e,
+	0x65, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62,
+	0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x03, 0x6e, 0x65, 0x77, 0x12, 0x17, 0x0a, 0x07, 0x6f, 0x6c,
+	0x64, 0x5f, 0x67, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x6c, 0x64,
+	0x47, 0x65, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x65, 0x77, 0x5f, 0x67, 0x65, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x65, 0x77, 0x47, 0x65, 0x6e, 0x12, 0x38, 0x0a, 0x09,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x12, 0x37, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x66,
+	0x61, 0x69, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x12,
+	0x3d, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x6d, 0x6f, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x6f, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x66, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x66,
+	0x61, 0x69, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x69,
+	0x67, 0x6e, 0x6f, 0x72, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x66, 0x61, 0x69, 0x6c,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x63, 0x61, 0x75, 0x73, 0x65, 0x73, 0x18,
+	0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61,
+	0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x75,
+	0x73, 0x65, 0x52, 0x06, 0x63, 0x61, 0x75, 0x73, 0x65, 0x73, 0x22, 0xb7, 0x01, 0x0a, 0x09, 0x53,
+	0x68, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61,
+	0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b,
+	0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x65, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x67, 0x65, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x70, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x65, 0x64, 0x22, 0xae, 0x01, 0x0a, 0x0c, 0x45, 0x76, 0x69, 0x63, 0x74, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72,
+	0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x38, 0x0a, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x12, 0x37, 0x0a, 0x09,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6c, 0x61, 0x73,
+	0x74, 0x55, 0x73, 0x65, 0x64, 0x22, 0x71, 0x0a, 0x0f, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61,
+	0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b,
+	0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2a, 0xf5, 0x01, 0x0a, 0x0b, 0x42, 0x72, 0x65,
+	0x61, 0x6b, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x42, 0x52, 0x45, 0x41,
+	0x4b, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x45,
+	0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x47, 0x4c, 0x4f, 0x42, 0x41, 0x4c, 0x10, 0x01, 0x12,
+	0x18, 0x0a, 0x14, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x42, 0x52, 0x45,
+	0x41, 0x4b, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44,
+	0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x45, 0x52, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x43, 0x41, 0x4c, 0x4c, 0x5f, 0x53, 0x49, 0x54, 0x45, 0x10, 0x04, 0x12, 0x18,
+	0x0a, 0x14, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43,
+	0x49, 0x52, 0x43, 0x55, 0x49, 0x54, 0x10, 0x05, 0x12, 0x15, 0x0a, 0x11, 0x42, 0x52, 0x45, 0x41,
+	0x4b, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x06, 0x12,
+	0x15, 0x0a, 0x11, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x50, 0x41, 0x54, 0x48, 0x10, 0x07, 0x12, 0x18, 0x0a, 0x14, 0x42, 0x52, 0x45, 0x41, 0x4b, 0x45,
+	0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x41, 0x43, 0x4b, 0x41, 0x47, 0x45, 0x10, 0x08,
+	0x2a, 0x51, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x54, 0x41,
+	0x54, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c,
+	0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x01, 0x12, 0x13,
+	0x0a, 0x0f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x48, 0x41, 0x4c, 0x46, 0x5f, 0x4f, 0x50, 0x45,
+	0x4e, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x4f, 0x50, 0x45,
+	0x4e, 0x10, 0x03, 0x32, 0xc3, 0x02, 0x0a, 0x0c, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x41,
+	0x64, 0x6d, 0x69, 0x6e, 0x12, 0x4d, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x21, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x20, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x12, 0x4a, 0x0a, 0x05, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x22, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b,
+	0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62,
+	0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x08, 0x4f, 0x76, 0x65, 0x72, 0x72,
+	0x69, 0x64, 0x65, 0x12, 0x25, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65,
+	0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x72,
+	0x69, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x77, 0x69, 0x6c, 0x6e, 0x65, 0x72, 0x2f,
+	0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x2f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x76, 0x31, 0x3b, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_grpcbreaker_admin_v1_admin_proto_rawDescOnce sync.Once
+	file_grpcbreaker_admin_v1_admin_proto_rawDescData = file_grpcbreaker_admin_v1_admin_proto_rawDesc
+)
+
+func file_grpcbreaker_admin_v1_admin_proto_rawDescGZIP() []byte {
+	file_grpcbreaker_admin_v1_admin_proto_rawDescOnce.Do(func() {
+		file_grpcbreaker_admin_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(file_grpcbreaker_admin_v1_admin_proto_rawDescData)
+	})
+	return file_grpcbreaker_admin_v1_admin_proto_rawDescData
+}
+
+var file_grpcbreaker_admin_v1_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_grpcbreaker_admin_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_grpcbreaker_admin_v1_admin_proto_goTypes = []interface{}{
+	(BreakerType)(0),            // 0: grpcbreaker.admin.v1.BreakerType
+	(State)(0),                  // 1: grpcbreaker.admin.v1.State
+	(*Key)(nil),                 // 2: grpcbreaker.admin.v1.Key
+	(*Breaker)(nil),             // 3: grpcbreaker.admin.v1.Breaker
+	(*Cause)(nil),               // 4: grpcbreaker.admin.v1.Cause
+	(*Filter)(nil),              // 5: grpcbreaker.admin.v1.Filter
+	(*ListRequest)(nil),         // 6: grpcbreaker.admin.v1.ListRequest
+	(*ListResponse)(nil),        // 7: grpcbreaker.admin.v1.ListResponse
+	(*GetRequest)(nil),          // 8: grpcbreaker.admin.v1.GetRequest
+	(*WatchRequest)(nil),        // 9: grpcbreaker.admin.v1.WatchRequest
+	(*Event)(nil),               // 10: grpcbreaker.admin.v1.Event
+	(*StateEvent)(nil),          // 11: grpcbreaker.admin.v1.StateEvent
+	(*ShedEvent)(nil),           // 12: grpcbreaker.admin.v1.ShedEvent
+	(*EvictedEvent)(nil),        // 13: grpcbreaker.admin.v1.EvictedEvent
+	(*OverrideRequest)(nil),     // 14: grpcbreaker.admin.v1.OverrideRequest
+	(*timestamp.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_grpcbreaker_admin_v1_admin_proto_depIdxs = []int32{
+	0,  // 0: grpcbreaker.admin.v1.Key.type:type_name -> grpcbreaker.admin.v1.BreakerType
+	2,  // 1: grpcbreaker.admin.v1.Breaker.key:type_name -> grpcbreaker.admin.v1.Key
+	1,  // 2: grpcbreaker.admin.v1.Breaker.state:type_name -> grpcbreaker.admin.v1.State
+	15, // 3: grpcbreaker.admin.v1.Breaker.last_fail:type_name -> google.protobuf.Timestamp
+	15, // 4: grpcbreaker.admin.v1.Breaker.last_used:type_name -> google.protobuf.Timestamp
+	15, // 5: grpcbreaker.admin.v1.Breaker.reset_moment:type_name -> google.protobuf.Timestamp
+	11, // 6: grpcbreaker.admin.v1.Breaker.transitions:type_name -> grpcbreaker.admin.v1.StateEvent
+	4,  // 7: grpcbreaker.admin.v1.Breaker.causes:type_name -> grpcbreaker.admin.v1.Cause
+	15, // 8: grpcbreaker.admin.v1.Cause.time:type_name -> google.protobuf.Timestamp
+	0,  // 9: grpcbreaker.admin.v1.Filter.types:type_name -> grpcbreaker.admin.v1.BreakerType
+	5,  // 10: grpcbreaker.admin.v1.ListRequest.filter:type_name -> grpcbreaker.admin.v1.Filter
+	3,  // 11: grpcbreaker.admin.v1.ListResponse.breakers:type_name -> grpcbreaker.admin.v1.Breaker
+	2,  // 12: grpcbreaker.admin.v1.GetRequest.key:type_name -> grpcbreaker.admin.v1.Key
+	5,  // 13: grpcbreaker.admin.v1.WatchRequest.filter:type_name -> grpcbreaker.admin.v1.Filter
+	11, // 14: grpcbreaker.admin.v1.Event.state:type_name -> grpcbreaker.admin.v1.StateEvent
+	12, // 15: grpcbreaker.admin.v1.Event.shed:type_name -> grpcbreaker.admin.v1.ShedEvent
+	13, // 16: grpcbreaker.admin.v1.Event.evicted:type_name -> grpcbreaker.admin.v1.EvictedEvent
+	2,  // 17: grpcbreaker.admin.v1.StateEvent.key:type_name -> grpcbreaker.admin.v1.Key
+	1,  // 18: grpcbreaker.admin.v1.StateEvent.old:type_name -> grpcbreaker.admin.v1.State
+	1,  // 19: grpcbreaker.admin.v1.StateEvent.new:type_name -> grpcbreaker.admin.v1.State
+	15, // 20: grpcbreaker.admin.v1.StateEvent.published:type_name -> google.protobuf.Timestamp
+	15, // 21: grpcbreaker.admin.v1.StateEvent.last_fail:type_name -> google.protobuf.Timestamp
+	15, // 22: grpcbreaker.admin.v1.StateEvent.reset_moment:type_name -> google.protobuf.Timestamp
+	4,  // 23: grpcbreaker.admin.v1.StateEvent.causes:type_name -> grpcbreaker.admin.v1.Cause
+	2,  // 24: grpcbreaker.admin.v1.ShedEvent.key:type_name -> grpcbreaker.admin.v1.Key
+	1,  // 25: grpcbreaker.admin.v1.ShedEvent.state:type_name -> grpcbreaker.admin.v1.State
+	15, // 26: grpcbreaker.admin.v1.ShedEvent.published:type_name -> google.protobuf.Timestamp
+	2,  // 27: grpcbreaker.admin.v1.EvictedEvent.key:type_name -> grpcbreaker.admin.v1.Key
+	15, // 28: grpcbreaker.admin.v1.EvictedEvent.published:type_name -> google.protobuf.Timestamp
+	15, // 29: grpcbreaker.admin.v1.EvictedEvent.last_used:type_name -> google.protobuf.Timestamp
+	2,  // 30: grpcbreaker.admin.v1.OverrideRequest.key:type_name -> grpcbreaker.admin.v1.Key
+	1,  // 31: grpcbreaker.admin.v1.OverrideRequest.state:type_name -> grpcbreaker.admin.v1.State
+	6,  // 32: grpcbreaker.admin.v1.BreakerAdmin.List:input_type -> grpcbreaker.admin.v1.ListRequest
+	8,  // 33: grpcbreaker.admin.v1.BreakerAdmin.Get:input_type -> grpcbreaker.admin.v1.GetRequest
+	9,  // 34: grpcbreaker.admin.v1.BreakerAdmin.Watch:input_type -> grpcbreaker.admin.v1.WatchRequest
+	14, // 35: grpcbreaker.admin.v1.BreakerAdmin.Override:input_type -> grpcbreaker.admin.v1.OverrideRequest
+	7,  // 36: grpcbreaker.admin.v1.BreakerAdmin.List:output_type -> grpcbreaker.admin.v1.ListResponse
+	3,  // 37: grpcbreaker.admin.v1.BreakerAdmin.Get:output_type -> grpcbreaker.admin.v1.Breaker
+	10, // 38: grpcbreaker.admin.v1.BreakerAdmin.Watch:output_type -> grpcbreaker.admin.v1.Event
+	3,  // 39: grpcbreaker.admin.v1.BreakerAdmin.Override:output_type -> grpcbreaker.admin.v1.Breaker
+	36, // [36:40] is the sub-list for method output_type
+	32, // [32:36] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
+}
+
+func init() { file_grpcbreaker_admin_v1_admin_proto_init() }
+func file_grpcbreaker_admin_v1_admin_proto_init() {
+	if File_grpcbreaker_admin_v1_admin_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Key); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Breaker); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Cause); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Filter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvictedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcbreaker_admin_v1_admin_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OverrideRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_grpcbreaker_admin_v1_admin_proto_msgTypes[8].OneofWrappers = []interface{}{
+		(*Event_State)(nil),
+		(*Event_Shed)(nil),
+		(*Event_Evicted)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_grpcbreaker_admin_v1_admin_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_grpcbreaker_admin_v1_admin_proto_goTypes,
+		DependencyIndexes: file_grpcbreaker_admin_v1_admin_proto_depIdxs,
+		EnumInfos:         file_grpcbreaker_admin_v1_admin_proto_enumTypes,
+		MessageInfos:      file_grpcbreaker_admin_v1_admin_proto_msgTypes,
+	}.Build()
+	File_grpcbreaker_admin_v1_admin_proto = out.File
+	file_grpcbreaker_admin_v1_admin_proto_rawDesc = nil
+	file_grpcbreaker_admin_v1_admin_proto_goTypes = nil
+	file_grpcbreaker_admin_v1_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,206 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/admin/adminv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the grpcbreaker.admin.v1.BreakerAdmin service; register it on any grpc.Server with
+// adminv1.RegisterBreakerAdminServer
+type Server struct {
+	adminv1.UnimplementedBreakerAdminServer
+
+	b *grpcbreaker.Breaker
+}
+
+// NewServer returns a Server for the breakers
+func NewServer(b *grpcbreaker.Breaker) *Server {
+	return &Server{b: b}
+}
+
+// List returns the breakers matching the filter
+func (s *Server) List(_ context.Context, req *adminv1.ListRequest) (*adminv1.ListResponse, error) {
+	match := matchFilter(req.GetFilter())
+
+	resp := new(adminv1.ListResponse)
+	for _, snap := range s.b.Snapshot() {
+		if match(snap.Key) {
+			resp.Breakers = append(resp.Breakers, breakerToProto(snap))
+		}
+	}
+	return resp, nil
+}
+
+// Get returns the breaker with the key
+func (s *Server) Get(_ context.Context, req *adminv1.GetRequest) (*adminv1.Breaker, error) {
+	key, err := keyFromProto(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	snap, ok := s.b.Get(key)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%v: %v", key, grpcbreaker.ErrNoBreaker)
+	}
+	return breakerToProto(snap), nil
+}
+
+// Watch streams the events matching the filter until the call ends
+func (s *Server) Watch(req *adminv1.WatchRequest, stream adminv1.BreakerAdmin_WatchServer) error {
+	match := matchFilter(req.GetFilter())
+
+	events := s.b.Subscribe(stream.Context(), 100)
+	// send headers straight away so that clients can tell they're subscribed
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for ev := range events {
+		pb, key := eventToProto(ev)
+		if pb == nil || !match(key) {
+			continue
+		}
+		if err := stream.Send(pb); err != nil {
+			return err
+		}
+	}
+	return stream.Context().Err()
+}
+
+// Override forces the breaker into the state
+func (s *Server) Override(_ context.Context, req *adminv1.OverrideRequest) (*adminv1.Breaker, error) {
+	key, err := keyFromProto(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetState() == adminv1.State_STATE_UNKNOWN {
+		return nil, status.Error(codes.InvalidArgument, "a state is required")
+	}
+
+	switch err := s.b.Override(key, grpcbreaker.State(req.GetState())); {
+	case errors.Is(err, grpcbreaker.ErrNoBreaker):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	snap, ok := s.b.Get(key)
+	if !ok { // stopped in the meantime
+		return nil, status.Errorf(codes.NotFound, "%v: %v", key, grpcbreaker.ErrNoBreaker)
+	}
+	return breakerToProto(snap), nil
+}
+
+func matchFilter(f *adminv1.Filter) func(grpcbreaker.Key) bool {
+	return func(k grpcbreaker.Key) bool {
+		if !strings.HasPrefix(k.Name, f.GetNamePrefix()) {
+			return false
+		}
+		if len(f.GetTypes()) == 0 {
+			return true
+		}
+		for _, t := range f.GetTypes() {
+			if t != adminv1.BreakerType_BREAKER_TYPE_UNSPECIFIED && grpcbreaker.BreakerType(t-1) == k.Type {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func breakerToProto(s grpcbreaker.Snapshot) *adminv1.Breaker {
+	b := &adminv1.Breaker{
+		Key:         keyToProto(s.Key),
+		State:       adminv1.State(s.State.State()),
+		Gen:         s.State.Gen(),
+		Fails:       int64(s.Fails),
+		Passes:      int64(s.Passes),
+		Ignored:     int64(s.Ignored),
+		FailScore:   s.FailScore,
+		LastFail:    timestampToProto(s.LastFail),
+		LastUsed:    timestampToProto(s.LastUsed),
+		ResetMoment: timestampToProto(s.ResetMoment),
+	}
+	for _, t := range s.Transitions {
+		ev, _ := eventToProto(t)
+		b.Transitions = append(b.Transitions, ev.GetState())
+	}
+	b.Causes = causesToProto(s.Causes)
+	return b
+}
+
+func causesToProto(causes []grpcbreaker.Cause) []*adminv1.Cause {
+	var pbs []*adminv1.Cause
+	for _, c := range causes {
+		pbs = append(pbs, &adminv1.Cause{
+			Method:  c.Method,
+			Code:    int32(c.Code),
+			Message: c.Message,
+			Time:    timestampToProto(c.Time),
+		})
+	}
+	return pbs
+}
+
+// eventToProto converts the event, returning nil for unknown events
+func eventToProto(ev grpcbreaker.Event) (*adminv1.Event, grpcbreaker.Key) {
+	switch e := ev.(type) {
+	case grpcbreaker.StateEvent:
+		return &adminv1.Event{Event: &adminv1.Event_State{State: &adminv1.StateEvent{
+			Key:         keyToProto(e.Key),
+			Old:         adminv1.State(e.Old.State()),
+			New:         adminv1.State(e.New.State()),
+			OldGen:      e.Old.Gen(),
+			NewGen:      e.New.Gen(),
+			Published:   timestampToProto(e.Published),
+			LastFail:    timestampToProto(e.LastFail),
+			ResetMoment: timestampToProto(e.ResetMoment),
+			Fails:       int64(e.Fails),
+			Passes:      int64(e.Passes),
+			Ignored:     int64(e.Ignored),
+			FailScore:   e.FailScore,
+			Causes:      causesToProto(e.Causes),
+		}}}, e.Key
+	case grpcbreaker.ShedEvent:
+		return &adminv1.Event{Event: &adminv1.Event_Shed{Shed: &adminv1.ShedEvent{
+			Key:       keyToProto(e.Key),
+			State:     adminv1.State(e.State.State()),
+			Gen:       e.State.Gen(),
+			Published: timestampToProto(e.Published),
+		}}}, e.Key
+	case grpcbreaker.EvictedEvent:
+		return &adminv1.Event{Event: &adminv1.Event_Evicted{Evicted: &adminv1.EvictedEvent{
+			Key:       keyToProto(e.Key),
+			Published: timestampToProto(e.Published),
+			LastUsed:  timestampToProto(e.LastUsed),
+		}}}, e.Key
+	}
+	return nil, grpcbreaker.Key{}
+}
+
+func keyToProto(k grpcbreaker.Key) *adminv1.Key {
+	return &adminv1.Key{Type: adminv1.BreakerType(k.Type + 1), Name: k.Name, Partition: k.Partition}
+}
+
+// keyFromProto returns an InvalidArgument error if the key's type is unset
+func keyFromProto(k *adminv1.Key) (grpcbreaker.Key, error) {
+	if k.GetType() == adminv1.BreakerType_BREAKER_TYPE_UNSPECIFIED {
+		return grpcbreaker.Key{}, status.Error(codes.InvalidArgument, "a breaker type is required")
+	}
+	t := grpcbreaker.BreakerType(k.GetType() - 1)
+	return grpcbreaker.Key{Type: t, Name: k.GetName(), Partition: k.GetPartition()}, nil
+}
+
+func timestampToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,118 @@
+package admin_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/admin"
+	"github.com/jwilner/grpcbreaker/admin/adminv1"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServer(t *testing.T) {
+	h := grpcbreakertest.NewBreaker(
+		t,
+		grpcbreaker.Global(grpcbreaker.ResetTimeout(time.Minute)),
+		grpcbreaker.Service("/pkg.Svc"),
+		grpcbreaker.Method("/pkg.Svc/Get"),
+	)
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	adminv1.RegisterBreakerAdminServer(srv, admin.NewServer(h.Breaker))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	cc, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+
+	client := adminv1.NewBreakerAdminClient(cc)
+	ctx := context.Background()
+	svc := &adminv1.Key{Type: adminv1.BreakerType_BREAKER_TYPE_SERVICE, Name: "/pkg.Svc"}
+
+	t.Run("lists", func(t *testing.T) {
+		resp, err := client.List(ctx, &adminv1.ListRequest{Filter: &adminv1.Filter{
+			Types: []adminv1.BreakerType{adminv1.BreakerType_BREAKER_TYPE_SERVICE, adminv1.BreakerType_BREAKER_TYPE_METHOD},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Breakers) != 2 || resp.Breakers[0].Key.Name != "/pkg.Svc" || resp.Breakers[1].Key.Name != "/pkg.Svc/Get" {
+			t.Fatalf("unexpected breakers %v", resp.Breakers)
+		}
+	})
+
+	t.Run("overrides and watches", func(t *testing.T) {
+		wctx, cncl := context.WithCancel(ctx)
+		defer cncl()
+		watch, err := client.Watch(wctx, &adminv1.WatchRequest{Filter: &adminv1.Filter{NamePrefix: "/pkg.Svc"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := watch.Header(); err != nil { // the server's subscribed once it's sent headers
+			t.Fatal(err)
+		}
+
+		b, err := client.Override(ctx, &adminv1.OverrideRequest{Key: svc, State: adminv1.State_STATE_OPEN})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.State != adminv1.State_STATE_OPEN || b.Gen != 1 || b.ResetMoment == nil || len(b.Transitions) != 1 {
+			t.Fatalf("unexpected breaker %v", b)
+		}
+
+		ev, err := watch.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st := ev.GetState(); st.GetKey().GetName() != "/pkg.Svc" || st.Old != adminv1.State_STATE_CLOSED || st.New != adminv1.State_STATE_OPEN {
+			t.Fatalf("unexpected event %v", ev)
+		}
+
+		if b, err := client.Get(ctx, &adminv1.GetRequest{Key: svc}); err != nil || b.State != adminv1.State_STATE_OPEN {
+			t.Fatalf("unexpected breaker %v, %v", b, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := client.Get(ctx, &adminv1.GetRequest{Key: &adminv1.Key{Type: adminv1.BreakerType_BREAKER_TYPE_METHOD, Name: "/pkg.Svc/List"}})
+		if c := status.Code(err); c != codes.NotFound {
+			t.Errorf("expected %v but got %v", codes.NotFound, err)
+		}
+
+		global := &adminv1.Key{Type: adminv1.BreakerType_BREAKER_TYPE_GLOBAL}
+		_, err = client.Override(ctx, &adminv1.OverrideRequest{Key: global, State: adminv1.State_STATE_HALF_OPEN})
+		if c := status.Code(err); c != codes.FailedPrecondition {
+			t.Errorf("expected %v but got %v", codes.FailedPrecondition, err)
+		}
+
+		// an unset type isn't taken for the global breaker
+		_, err = client.Get(ctx, &adminv1.GetRequest{Key: &adminv1.Key{}})
+		if c := status.Code(err); c != codes.InvalidArgument {
+			t.Errorf("expected %v but got %v", codes.InvalidArgument, err)
+		}
+		_, err = client.Override(ctx, &adminv1.OverrideRequest{Key: &adminv1.Key{}, State: adminv1.State_STATE_OPEN})
+		if c := status.Code(err); c != codes.InvalidArgument {
+			t.Errorf("expected %v but got %v", codes.InvalidArgument, err)
+		}
+
+		_, err = client.Override(ctx, &adminv1.OverrideRequest{Key: svc})
+		if c := status.Code(err); c != codes.InvalidArgument {
+			t.Errorf("expected %v but got %v", codes.InvalidArgument, err)
+		}
+	})
+}
@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"sync/atomic"
 	"time"
+	"unsafe"
+
+	"google.golang.org/grpc/metadata"
 )
 
 var (
@@ -17,167 +21,300 @@ var (
 )
 
 func newBreaker(key Key, deps deps, s settings) *breaker {
-	return &breaker{
-		Key:         key,
-		deps:        deps,
-		genState:    uint64(Closed), // gen 0, State closed
-		genOutcomes: make(chan genOutcome),
-		settings:    s,
+	b := &breaker{
+		Key:      key,
+		deps:     deps,
+		settings: s,
+
+		lastUsedNanos: deps.clock.Now().UnixNano(),
+	}
+	b.gen = unsafe.Pointer(&generation{GenState: GenState(Closed), causes: b.newCauses()}) // gen 0, State closed
+	if s.keyFunc != nil {
+		b.partitions = newPartitions(s.maxPartitions)
 	}
+	if s.historySize > 0 {
+		b.history = newHistory(s.historySize)
+	}
+	select {
+	case <-deps.closeCh:
+		b.gen = unsafe.Pointer(stopped)
+		return b
+	default:
+	}
+	if deps.restored != nil {
+		if cp, ok := deps.restored.LoadAndDelete(key); ok {
+			b.restore(cp.(Checkpoint))
+		}
+	}
+	return b
 }
 
 type deps struct {
-	closeCh <-chan struct{}
-	events  chan<- Event
+	closeCh  <-chan struct{}
+	events   chan<- Event
+	clock    Clock
+	sched    *scheduler
+	restored *sync.Map // of Key to Checkpoint, consumed by newBreaker; nil unless persisting
+	subs     *subscribers
+}
+
+func newDeps(closeCh <-chan struct{}, events chan<- Event, clock Clock) deps {
+	return deps{closeCh: closeCh, events: events, clock: clock, sched: newScheduler(clock), subs: new(subscribers)}
+}
+
+func (d deps) publish(ev Event) {
+	select {
+	case d.events <- ev:
+	default:
+	}
+	d.subs.publish(ev)
 }
 
+// breaker is the core state machine. It runs no goroutines of its own: transitions are made by callers atomically
+// swapping in a new generation, and timed transitions are made by the scheduler shared by all breakers.
 type breaker struct {
 	Key
 	settings
 	deps
 
-	genState    uint64 // atomic, only written to by `run`
-	genOutcomes chan genOutcome
+	gen unsafe.Pointer // atomic *generation
+
+	partitions *partitions // nil unless settings.keyFunc is set
+	history    *history    // nil unless settings.historySize is set
+
+	lastUsedNanos, lastFailNanos int64  // atomic
+	onIdle                       func() // set for dynamically created breakers, which are evicted once idle
 }
 
+// generation is a single GenState of a breaker along with the counters accumulated while in it. Outcomes are always
+// recorded against the generation in which their call started, so those recorded against a stale generation are
+// dropped.
+type generation struct {
+	GenState
+	resetMoment time.Time
+
+	fails, passes, ignored int64 // atomic
+	failScore              int64 // atomic, in units of 1/scoreScale
+
+	causes   []unsafe.Pointer // atomic *causeEntry, a ring of the last settings.maxCauses failures
+	causeSeq uint64           // atomic, the number of causes recorded
+
+	byPeers bool // opened because of peers' reports rather than its own failures
+}
+
+// scoreScale is the precision with which weighted failures are accumulated
+const scoreScale = 1000
+
+// stopped is the generation of every stopped breaker
+var stopped = &generation{}
+
 func init() {
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 }
 
-func (b *breaker) call(ctx context.Context, circuit func(ctx context.Context) error) error {
-	genState := GenState(atomic.LoadUint64(&b.genState))
+func (b *breaker) call(
+	ctx context.Context,
+	method string,
+	circuit func(ctx context.Context) (metadata.MD, error),
+) error {
+	start := b.clock.Now()
+	atomic.StoreInt64(&b.lastUsedNanos, start.UnixNano())
 
-	if genState == 0 {
-		return circuit(ctx)
-	}
+	g := b.load()
 
-	state := genState.State()
+	if g == stopped {
+		_, err := circuit(ctx)
+		return err
+	}
 
-	if state == Open {
-		select {
-		case b.events <- ShedEvent{b.Key, time.Now(), genState}:
-		default:
+	if g.State() == Open {
+		ev := ShedEvent{b.Key, start, g.GenState}
+		if b.history != nil {
+			b.history.addShed(ev)
 		}
+		b.publish(ev)
 		return ErrBreakerOpen
 	}
 
-	err := circuit(ctx)
+	trailer, err := circuit(ctx)
+	info := CallInfo{Method: method, Err: err, Elapsed: b.clock.Now().Sub(start), Trailer: trailer}
+	c := b.classify(ctx, info)
+	if c.Outcome == Failure {
+		b.recordCause(g, info) // before recording the outcome, so that it's included should the breaker trip
+	}
+	b.record(g, c)
+	return err
+}
+
+// classify determines the Classification of a call; errors caused by the caller's own context (e.g. a cancellation
+// or a too-short deadline) say nothing about the health of the circuit and are always ignored.
+func (b *breaker) classify(ctx context.Context, info CallInfo) Classification {
+	if info.Err != nil && ctx.Err() != nil {
+		return Classification{Outcome: Ignored}
+	}
+	c := b.classifier.Classify(ctx, info)
+	var hint interface{ RetryAfter() time.Duration }
+	if c.Outcome == Failure && c.RetryAfter == 0 && errors.As(info.Err, &hint) {
+		c.RetryAfter = hint.RetryAfter()
+	}
+	return c
+}
+
+// record accumulates a classification against the generation its call started in, transitioning if that crosses a
+// threshold
+func (b *breaker) record(g *generation, c Classification) {
 	switch {
-	case err != nil && b.predicate(err):
-		select {
-		case b.genOutcomes <- genState.asFail():
-		case <-b.closeCh:
-		case <-ctx.Done():
+	case c.Outcome == Ignored:
+		atomic.AddInt64(&g.ignored, 1)
+
+	case c.Outcome == Failure:
+		atomic.AddInt64(&g.fails, 1)
+		score := atomic.AddInt64(&g.failScore, int64(c.weight()*scoreScale))
+		atomic.StoreInt64(&b.lastFailNanos, b.clock.Now().UnixNano())
+
+		if g.State() == HalfOpen || score >= int64(b.failThreshold)*scoreScale {
+			// closed -> open
+			// half open -> open
+			reset := b.reset
+			if reset > 0 && c.RetryAfter > reset {
+				reset = c.RetryAfter
+			}
+			b.transitionFor(g, Open, reset)
+			return
 		}
-	case state == HalfOpen:
-		select {
-		case b.genOutcomes <- genState.asPass():
-		case <-b.closeCh:
-		case <-ctx.Done():
+
+	case g.State() == HalfOpen:
+		if atomic.AddInt64(&g.passes, 1) >= int64(b.resetThreshold) {
+			// half open -> closed
+			b.transition(g, Closed)
+			return
 		}
+
+	default:
+		return // successes are only of interest when half open
+	}
+
+	if b.load() == g { // drop outcomes not from this gen
+		b.publishState(g, g)
 	}
-	return err
 }
 
-func (b *breaker) run() {
-	var (
-		fails, passes         int
-		lastFail, resetMoment time.Time
-		resetTimer            *time.Timer
-	)
+// transition moves the breaker from the given generation to a new one in the given state, doing nothing and returning
+// false if the given generation is no longer current
+func (b *breaker) transition(from *generation, state State) bool {
+	return b.transitionFor(from, state, b.reset)
+}
 
-	if b.reset > 0 {
-		resetTimer = time.NewTimer(0)
+// transitionFor is transition with the given reset timeout in place of the breaker's own
+func (b *breaker) transitionFor(from *generation, state State, reset time.Duration) bool {
+	return b.swap(from, b.next(from, state, reset))
+}
+
+// next returns the generation following the given one in the given state
+func (b *breaker) next(from *generation, state State, reset time.Duration) *generation {
+	to := &generation{GenState: from.Next(state), causes: b.newCauses()}
+	if state == Open && reset > 0 {
+		to.resetMoment = b.clock.Now().Add(reset)
 	}
+	return to
+}
 
-	for {
-		genState := GenState(atomic.LoadUint64(&b.genState))
-		state := genState.State()
+// swap replaces the given generation with the next, doing nothing and returning false if the given generation is no
+// longer current
+func (b *breaker) swap(from, to *generation) bool {
+	if !atomic.CompareAndSwapPointer(&b.gen, unsafe.Pointer(from), unsafe.Pointer(to)) {
+		return false // someone else got here first
+	}
+
+	// publish before scheduling the reset so that even very short reset timeouts are published in order
+	b.publishState(from, to)
+
+	if !to.resetMoment.IsZero() {
+		// open -> half open
+		b.sched.schedule(to.resetMoment, func() { b.transition(to, HalfOpen) })
+	}
+	return true
+}
 
-		var resetCh <-chan time.Time
-		if resetTimer != nil {
-			resetCh = resetTimer.C
+func (b *breaker) publishState(from, to *generation) {
+	ev := StateEvent{
+		Key:         b.Key,
+		Published:   b.clock.Now(),
+		Old:         from.GenState,
+		New:         to.GenState,
+		LastFail:    b.lastFail(),
+		ResetMoment: to.resetMoment,
+		Fails:       int(atomic.LoadInt64(&from.fails)),
+		FailScore:   float64(atomic.LoadInt64(&from.failScore)) / scoreScale,
+		Passes:      int(atomic.LoadInt64(&from.passes)),
+		Ignored:     int(atomic.LoadInt64(&from.ignored)),
+	}
+	if ev.Transition() {
+		ev.Causes = from.recentCauses()
+		if b.history != nil {
+			b.history.addTransition(ev)
 		}
+	}
+	b.publish(ev)
+}
 
-		select {
-
-		case res := <-b.genOutcomes:
-			switch {
-			case res.gen() != genState.Gen():
-				continue // drop messages not from this gen
-
-			case res.pass():
-				passes++
-				if passes >= b.resetThreshold {
-					// half open -> closed
-					fails = 0
-					state = Closed
-				}
-
-			default: // fail
-				fails++
-				lastFail = time.Now()
-
-				if fails < b.failThreshold {
-					break
-				}
-
-				// closed -> open
-				// half open -> open
-				state = Open
-
-				if resetTimer == nil {
-					break
-				}
-
-				// start the resetTimer anew
-				if !resetTimer.Stop() { // drain if need be
-					select {
-					case <-resetCh:
-					default:
-					}
-				}
-
-				resetMoment = lastFail.Add(b.reset)
-				resetTimer.Reset(time.Until(resetMoment))
-			}
+// start schedules any checks the breaker needs in the background
+func (b *breaker) start() {
+	if b.idleTTL > 0 && b.onIdle != nil {
+		b.sched.schedule(b.lastUsed().Add(b.idleTTL), b.checkIdle)
+	}
+}
 
-		case <-resetCh:
-			if state != Open { // if the timer is expiring when we're not in Open, ignore
-				continue
-			}
+// checkIdle stops and evicts the breaker if it's closed and has been unused for the idle TTL, or otherwise schedules
+// another check
+func (b *breaker) checkIdle() {
+	g := b.load()
+	if g == stopped {
+		return
+	}
 
-			resetMoment = time.Time{}
-			// open -> half open
-			state = HalfOpen
+	lastUsed := b.lastUsed()
+	switch {
+	case g.State() != Closed: // only closed breakers are evicted; check again later
+		b.sched.schedule(b.clock.Now().Add(b.idleTTL), b.checkIdle)
+		return
+	case b.clock.Now().Sub(lastUsed) < b.idleTTL:
+		b.sched.schedule(lastUsed.Add(b.idleTTL), b.checkIdle)
+		return
+	}
 
-		case <-b.closeCh:
-			atomic.StoreUint64(&b.genState, 0)
-			return
-		}
+	if !atomic.CompareAndSwapPointer(&b.gen, unsafe.Pointer(g), unsafe.Pointer(stopped)) {
+		b.checkIdle() // the state changed underneath us; reconsider
+		return
+	}
 
-		newState := genState
-		if genState.State() != state {
-			newState = genState.Next(state)
-			atomic.StoreUint64(&b.genState, uint64(newState))
-		}
+	b.onIdle()
+	b.publish(EvictedEvent{b.Key, b.clock.Now(), lastUsed})
+}
 
-		select {
-		case b.events <- StateEvent{
-			Key:         b.Key,
-			Published:   time.Now(),
-			Old:         genState,
-			New:         newState,
-			LastFail:    lastFail,
-			ResetMoment: resetMoment,
-			Fails:       fails,
-			Passes:      passes,
-		}:
-		default:
-		}
+// stop permanently disables the breaker and any partitions, after which all calls pass through
+func (b *breaker) stop() {
+	atomic.StorePointer(&b.gen, unsafe.Pointer(stopped))
+	if b.partitions != nil {
+		b.partitions.stopAll()
 	}
 }
 
+func (b *breaker) load() *generation {
+	return (*generation)(atomic.LoadPointer(&b.gen))
+}
+
+func (b *breaker) lastUsed() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&b.lastUsedNanos))
+}
+
+func (b *breaker) lastFail() time.Time {
+	if n := atomic.LoadInt64(&b.lastFailNanos); n != 0 {
+		return time.Unix(0, n)
+	}
+	return time.Time{}
+}
+
 // State is the current state of the breaker -- closed, half open, or open
 type State uint64
 
@@ -189,10 +326,19 @@ const (
 	Open
 )
 
+// Outcome is the classification of a single call through the breaker
+type Outcome uint64
+
+//go:generate stringer -type=Outcome
+const (
+	Success Outcome = iota
+	Failure
+	Ignored
+)
+
 const (
-	stateBits   = 2
-	outcomeBit  = 1
-	outcomePass = 0
+	stateBits = 2
+	stateMask = (1 << stateBits) - 1
 )
 
 // GenState is the current gen and State of the breaker
@@ -200,7 +346,7 @@ type GenState uint64
 
 // State returns the current State for the breaker
 func (g GenState) State() State {
-	return State(g) & ((1 << stateBits) - 1)
+	return State(g) & stateMask
 }
 
 // String returns a friendly representation of this GenState
@@ -208,14 +354,6 @@ func (g GenState) String() string {
 	return fmt.Sprintf("%d|%v", g.Gen(), g.State())
 }
 
-func (g GenState) asFail() genOutcome {
-	return genOutcome(g) | outcomeBit
-}
-
-func (g GenState) asPass() genOutcome {
-	return genOutcome(g) &^ outcomeBit
-}
-
 // Gen returns the generation of this GenState
 func (g GenState) Gen() uint64 {
 	return uint64(g) >> 2
@@ -225,13 +363,3 @@ func (g GenState) Gen() uint64 {
 func (g GenState) Next(state State) GenState {
 	return GenState(g.Gen()+1)<<2 | GenState(state)
 }
-
-type genOutcome uint64
-
-func (g genOutcome) pass() bool {
-	return g&outcomeBit == outcomePass
-}
-
-func (g genOutcome) gen() uint64 {
-	return uint64(g) >> 2
-}
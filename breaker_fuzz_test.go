@@ -0,0 +1,212 @@
+//go:build go1.18
+// +build go1.18
+
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker/internal/clock"
+	"google.golang.org/grpc/metadata"
+)
+
+// model is a deliberately naive reference implementation of the breaker's state machine
+type model struct {
+	failThreshold, resetThreshold int
+	reset                         time.Duration
+
+	now, resetAt           time.Time
+	state                  State
+	fails, passes, ignored int
+}
+
+func (m *model) fail() {
+	switch m.state {
+	case Closed:
+		m.fails++
+		if m.fails >= m.failThreshold {
+			m.open()
+		}
+	case HalfOpen:
+		m.open()
+	}
+}
+
+func (m *model) pass() {
+	if m.state != HalfOpen {
+		return
+	}
+	m.passes++
+	if m.passes >= m.resetThreshold {
+		m.enter(Closed)
+	}
+}
+
+func (m *model) ignore() {
+	if m.state != Open {
+		m.ignored++
+	}
+}
+
+func (m *model) tick(d time.Duration) {
+	m.now = m.now.Add(d)
+	if m.state == Open && m.reset > 0 && !m.now.Before(m.resetAt) {
+		m.enter(HalfOpen)
+	}
+}
+
+func (m *model) open() {
+	m.enter(Open)
+	m.resetAt = m.now.Add(m.reset)
+}
+
+func (m *model) enter(s State) {
+	m.state = s
+	m.fails, m.passes, m.ignored = 0, 0, 0
+}
+
+// FuzzBreaker drives the breaker and the model with the same sequence of operations, checking that they agree and
+// that the breaker's transitions are always valid. The first three bytes of the input choose the settings and each
+// subsequent byte is an operation: the low three bits select it and the rest parameterize it.
+func FuzzBreaker(f *testing.F) {
+	f.Add([]byte{1, 0, 1, 1, 2 | 4<<3, 0, 0})
+	f.Add([]byte{3, 2, 5, 1, 1, 3, 1, 2 | 31<<3, 0, 4, 0, 1, 2 | 1<<3, 0})
+	f.Add([]byte{0, 1, 0, 1, 4, 0, 2 | 10<<3, 1})
+
+	errNope := errors.New("nope")
+	canceled, cncl := context.WithCancel(context.Background())
+	cncl()
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		if len(input) < 3 {
+			t.Skip()
+		}
+
+		m := &model{
+			failThreshold:  int(input[0] % 5),
+			resetThreshold: int(input[1] % 4),
+			reset:          time.Duration(input[2]%8) * time.Second,
+			now:            time.Unix(0, 0),
+			state:          Closed,
+		}
+
+		clk := clock.NewFake(m.now)
+		events := make(chan Event, 100)
+		ch := make(chan struct{})
+		defer close(ch)
+
+		var s settings
+		for _, o := range []Option{
+			Predicate(func(err error) bool { return true }),
+			FailThreshold(m.failThreshold),
+			ResetThreshold(m.resetThreshold),
+			ResetTimeout(m.reset),
+		} {
+			o(&s)
+		}
+		b := newBreaker(Key{}, newDeps(ch, events, clk), s)
+		b.start()
+
+		var (
+			stale   *generation // the most recent generation to have been superseded
+			lastGen = b.load().GenState
+		)
+
+		for i, op := range input[3:] {
+			before := b.load()
+
+			switch op & 7 {
+			case 0, 5:
+				_ = b.call(context.Background(), "", func(context.Context) (metadata.MD, error) { return nil, nil })
+				m.pass()
+			case 1, 6:
+				_ = b.call(context.Background(), "", func(context.Context) (metadata.MD, error) { return nil, errNope })
+				if m.state != Open {
+					m.fail()
+				}
+			case 2:
+				d := time.Duration(op>>3) * time.Second / 4
+				clk.Advance(d)
+				m.tick(d)
+			case 3:
+				_ = b.call(canceled, "", func(ctx context.Context) (metadata.MD, error) { return nil, ctx.Err() })
+				m.ignore()
+			case 4:
+				if stale != nil { // outcomes of superseded generations must always be dropped
+					b.record(stale, Classification{Outcome: Failure})
+					b.record(stale, Classification{Outcome: Success})
+				}
+			case 7:
+				continue
+			}
+
+			if after := b.load(); after != before {
+				stale = before
+			}
+
+			for drained := false; !drained; {
+				select {
+				case ev := <-events:
+					e, ok := ev.(StateEvent)
+					if !ok || !e.Transition() {
+						continue
+					}
+					if e.New.Gen() != e.Old.Gen()+1 || e.Old != lastGen {
+						t.Fatalf("op %d: generations not monotonic: %v -> %v after %v", i, e.Old, e.New, lastGen)
+					}
+					switch old, new := e.Old.State(), e.New.State(); {
+					case old == new,
+						old == Closed && new == HalfOpen,
+						old == Open && new == Closed:
+						t.Fatalf("op %d: invalid transition %v -> %v", i, old, new)
+					}
+					lastGen = e.New
+				default:
+					drained = true
+				}
+			}
+
+			g := b.load()
+			if g.GenState != lastGen {
+				t.Fatalf("op %d: current generation %v was never published; last was %v", i, g.GenState, lastGen)
+			}
+			if g.State() != m.state {
+				t.Fatalf("op %d: expected state %v but got %v", i, m.state, g.State())
+			}
+			if int(g.fails) != m.fails || int(g.passes) != m.passes || int(g.ignored) != m.ignored {
+				t.Fatalf(
+					"op %d: expected fails, passes, ignored of %d, %d, %d but got %d, %d, %d",
+					i, m.fails, m.passes, m.ignored, g.fails, g.passes, g.ignored,
+				)
+			}
+		}
+	})
+}
+
+// FuzzGenState checks the bit packing of GenState
+func FuzzGenState(f *testing.F) {
+	f.Add(uint64(0), uint8(1))
+	f.Add(uint64(1)<<61, uint8(3))
+	f.Add(^uint64(0)>>2, uint8(2))
+
+	f.Fuzz(func(t *testing.T, gen uint64, state uint8) {
+		gen &= ^uint64(0) >> stateBits // only 62 bits of generation
+		s := State(state%3) + Closed
+
+		g := GenState(gen<<stateBits) | GenState(s)
+		if g.Gen() != gen || g.State() != s {
+			t.Fatalf("%v: expected gen %d and state %v", g, gen, s)
+		}
+
+		next := g.Next(Open)
+		if next.State() != Open {
+			t.Fatalf("%v: expected state %v but got %v", next, Open, next.State())
+		}
+		if expected := (gen + 1) & (^uint64(0) >> stateBits); next.Gen() != expected {
+			t.Fatalf("%v: expected gen %d but got %d", next, expected, next.Gen())
+		}
+	})
+}
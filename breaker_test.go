@@ -3,14 +3,25 @@ package grpcbreaker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/jwilner/grpcbreaker/internal/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestBreaker(t *testing.T) {
 	errNope := errors.New("nope")
-	circuitNope := func(ctx context.Context) error { return errNope }
-	circuitOK := func(ctx context.Context) error { return nil }
+	circuitNope := func(ctx context.Context) (metadata.MD, error) { return nil, errNope }
+	circuitOK := func(ctx context.Context) (metadata.MD, error) { return nil, nil }
 	ctx := context.Background()
 
 	requireErr := func(t *testing.T, expected, err error) {
@@ -29,35 +40,113 @@ func TestBreaker(t *testing.T) {
 
 	t.Run("opens", func(t *testing.T) {
 		b := newTestBreaker(t, FailThreshold(1))
-		requireErr(t, errNope, b.call(ctx, circuitNope))
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
 		b.assertSequence(Closed, Open)
-		requireErr(t, ErrBreakerOpen, b.call(ctx, circuitOK))
+		requireErr(t, ErrBreakerOpen, b.call(ctx, "", circuitOK))
 	})
 
 	t.Run("half open success", func(t *testing.T) {
-		b := newTestBreaker(t, FailThreshold(1), ResetTimeout(time.Microsecond*10))
-		requireErr(t, errNope, b.call(ctx, circuitNope))
-		b.assertSequence(Closed, Open, HalfOpen)
-		requireNoErr(t, b.call(ctx, circuitOK))
+		b := newTestBreaker(t, FailThreshold(1), ResetTimeout(time.Second))
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
+		b.assertSequence(Closed, Open)
+		b.clock.Advance(time.Second - 1)
+		b.assertNoChange()
+		b.clock.Advance(1)
+		b.assertSequence(Open, HalfOpen)
+		requireNoErr(t, b.call(ctx, "", circuitOK))
 		b.assertSequence(HalfOpen, Closed)
 	})
 
 	t.Run("half open fail", func(t *testing.T) {
-		b := newTestBreaker(t, FailThreshold(1), ResetTimeout(time.Microsecond*10))
-		requireErr(t, errNope, b.call(ctx, circuitNope))
+		b := newTestBreaker(t, FailThreshold(1), ResetTimeout(time.Second))
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
+		b.clock.Advance(time.Second)
 		b.assertSequence(Closed, Open, HalfOpen)
-		requireErr(t, errNope, b.call(ctx, circuitNope))
-		b.assertSequence(HalfOpen, Open, HalfOpen) // final half open is because of short reset timeout
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
+		b.clock.Advance(time.Second)
+		b.assertSequence(HalfOpen, Open, HalfOpen)
 	})
 
 	t.Run("consecutive failures below threshold", func(t *testing.T) {
 		b := newTestBreaker(t, FailThreshold(3))
-		requireErr(t, errNope, b.call(ctx, circuitNope))
-		requireErr(t, errNope, b.call(ctx, circuitNope))
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
+		b.assertNoChange()
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
+		b.assertSequence(Closed, Open)
+	})
+
+	t.Run("caller cancellation ignored", func(t *testing.T) {
+		b := newTestBreaker(t, FailThreshold(1))
+		cctx, cncl := context.WithCancel(ctx)
+		cncl()
+		requireErr(t, context.Canceled, b.call(cctx, "", func(ctx context.Context) (metadata.MD, error) {
+			return nil, ctx.Err()
+		}))
+		b.assertNoChange()
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
+		b.assertSequence(Closed, Open)
+	})
+
+	t.Run("weighted failures", func(t *testing.T) {
+		half := ClassifierFunc(func(context.Context, CallInfo) Classification {
+			return Classification{Outcome: Failure, Weight: 0.5}
+		})
+		b := newTestBreaker(t, FailThreshold(1), Classify(half))
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
 		b.assertNoChange()
-		requireErr(t, errNope, b.call(ctx, circuitNope))
+		requireErr(t, errNope, b.call(ctx, "", circuitNope))
 		b.assertSequence(Closed, Open)
 	})
+
+	t.Run("classifier sees call info", func(t *testing.T) {
+		var got CallInfo
+		b := newTestBreaker(t, Classify(ClassifierFunc(func(_ context.Context, info CallInfo) Classification {
+			got = info
+			return Classification{Outcome: Success}
+		})))
+		trailer := metadata.Pairs("retry", "no")
+		requireErr(t, errNope, b.call(ctx, "/foo/Get", func(context.Context) (metadata.MD, error) {
+			return trailer, errNope
+		}))
+		if got.Method != "/foo/Get" || got.Err != errNope || !reflect.DeepEqual(got.Trailer, trailer) {
+			t.Fatalf("unexpected call info %+v", got)
+		}
+	})
+}
+
+func TestBreaker_causes(t *testing.T) {
+	b := newTestBreaker(t, FailThreshold(3), Causes(2), RedactCauses(func(c Cause) Cause {
+		c.Message = strings.ReplaceAll(c.Message, "secret", "***")
+		return c
+	}))
+
+	for i, code := range []codes.Code{codes.Internal, codes.Unavailable, codes.DeadlineExceeded} {
+		_ = b.call(context.Background(), fmt.Sprint("/foo/", i), func(context.Context) (metadata.MD, error) {
+			return nil, status.Error(code, "secret stuff")
+		})
+		b.clock.Advance(time.Second)
+	}
+
+	var transition StateEvent
+	for len(b.events) > 0 {
+		if ev, ok := (<-b.events).(StateEvent); ok && ev.Transition() {
+			transition = ev
+		}
+	}
+
+	want := []Cause{
+		{Method: "/foo/1", Code: codes.Unavailable, Message: "*** stuff", Time: time.Unix(1, 0)},
+		{Method: "/foo/2", Code: codes.DeadlineExceeded, Message: "*** stuff", Time: time.Unix(2, 0)},
+	}
+	if transition.New.State() != Open || !reflect.DeepEqual(transition.Causes, want) {
+		t.Fatalf("expected a transition to open caused by %+v but got %+v", want, transition)
+	}
+
+	// the new state starts afresh
+	if s, _ := b.snapshot(); len(s.Causes) != 0 {
+		t.Fatalf("unexpected snapshot %+v", s)
+	}
 }
 
 func newTestBreaker(t *testing.T, opts ...Option) *harness {
@@ -68,27 +157,29 @@ func newTestBreaker(t *testing.T, opts ...Option) *harness {
 
 	ch := make(chan struct{})
 	evs := make(chan Event, 100)
-	b := newBreaker(Key{}, deps{ch, evs}, s)
-	go b.run()
+	clk := clock.NewFake(time.Unix(0, 0))
+	b := newBreaker(Key{}, newDeps(ch, evs, clk), s)
+	b.start()
 
 	t.Cleanup(func() { close(ch) })
 
-	return &harness{b, t, evs}
+	return &harness{b, t, evs, clk}
 }
 
+// harness wraps a breaker with a fake clock; as the breaker publishes events synchronously with calls and with
+// advancing the clock, there's never any need to wait for them
 type harness struct {
 	*breaker
 
 	t *testing.T
 
 	events <-chan Event
+	clock  *clock.Fake
 }
 
 func (h *harness) assertNoChange() {
 	h.t.Helper()
 
-	delay := time.After(100 * time.Microsecond)
-
 	for {
 		select {
 		case ev := <-h.events:
@@ -97,7 +188,7 @@ func (h *harness) assertNoChange() {
 				continue
 			}
 			h.t.Fatalf("Wanted no change but got transition %v->%v", t.Old.State(), t.New.State())
-		case <-delay:
+		default:
 			return
 		}
 	}
@@ -106,8 +197,6 @@ func (h *harness) assertNoChange() {
 func (h *harness) assertSequence(start State, states ...State) {
 	h.t.Helper()
 
-	timeout := time.After(100 * time.Microsecond)
-
 	current := start
 	for len(states) > 0 {
 		next := states[0]
@@ -122,8 +211,49 @@ func (h *harness) assertSequence(start State, states ...State) {
 			}
 			current = next
 			states = states[1:]
-		case <-timeout:
-			h.t.Fatalf("Timed out waiting for state event")
+		default:
+			h.t.Fatalf("Missing state event %v->%v", current, next)
+		}
+	}
+}
+
+func BenchmarkBreaker(b *testing.B) {
+	errNope := errors.New("nope")
+	ctx := context.Background()
+
+	for _, bm := range []struct {
+		name    string
+		circuit func(ctx context.Context) (metadata.MD, error)
+	}{
+		{"success", func(ctx context.Context) (metadata.MD, error) { return nil, nil }},
+		{"failure", func(ctx context.Context) (metadata.MD, error) { return nil, errNope }},
+	} {
+		for _, goroutines := range []int{1, 8, 64} {
+			b.Run(fmt.Sprintf("%v/goroutines=%d", bm.name, goroutines), func(b *testing.B) {
+				var s settings
+				Predicate(func(error) bool { return true })(&s)
+				FailThreshold(math.MaxInt32)(&s) // never trips, so every failure is recorded
+
+				ch := make(chan struct{})
+				defer close(ch)
+				br := newBreaker(Key{}, newDeps(ch, make(chan Event, 100), clock.Real{}), s)
+				br.start()
+
+				remaining := int64(b.N)
+				var wg sync.WaitGroup
+				wg.Add(goroutines)
+
+				b.ResetTimer()
+				for i := 0; i < goroutines; i++ {
+					go func() {
+						defer wg.Done()
+						for atomic.AddInt64(&remaining, -1) >= 0 {
+							_ = br.call(ctx, "", bm.circuit)
+						}
+					}()
+				}
+				wg.Wait()
+			})
 		}
 	}
 }
@@ -12,11 +12,15 @@ func _() {
 	_ = x[BreakerService-1]
 	_ = x[BreakerMethod-2]
 	_ = x[BreakerCallSite-3]
+	_ = x[BreakerCircuit-4]
+	_ = x[BreakerHost-5]
+	_ = x[BreakerPath-6]
+	_ = x[BreakerPackage-7]
 }
 
-const _BreakerType_name = "BreakerGlobalBreakerServiceBreakerMethodBreakerCallSite"
+const _BreakerType_name = "BreakerGlobalBreakerServiceBreakerMethodBreakerCallSiteBreakerCircuitBreakerHostBreakerPathBreakerPackage"
 
-var _BreakerType_index = [...]uint8{0, 13, 27, 40, 55}
+var _BreakerType_index = [...]uint8{0, 13, 27, 40, 55, 69, 80, 91, 105}
 
 func (i BreakerType) String() string {
 	if i < 0 || i >= BreakerType(len(_BreakerType_index)-1) {
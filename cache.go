@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
 )
@@ -18,45 +19,54 @@ const (
 	BreakerService
 	BreakerMethod
 	BreakerCallSite
+	BreakerCircuit // a standalone Circuit
+	BreakerHost    // an HTTP host
+	BreakerPath    // an HTTP host and path prefix
+	BreakerPackage // a proto package, parenting its services and any nested packages
 )
 
 type cache struct {
-	m      sync.Map
-	global *breaker
+	m         sync.Map
+	global    *breaker
+	callSites int64        // atomic, the number of call site breakers in m
+	hosts     int64        // atomic, the number of Host breakers in m created for hosts without option sets
+	matched   int64        // atomic, the number of breakers in m created for names matching patterns
+	patterns  []*OptionSet // the ServiceGlob, MethodGlob, etc. option sets
+
+	closeMu sync.Mutex
+	onClose []func() // run once ctx is done, before the breakers are stopped
 }
 
-func newCache(deps deps, defaults []Option, g *GlobalOptionSet, optionSets ...*OptionSet) *cache {
-	// init the tree of Global, Service, and Method option sets
-	// Options copy from Global -> Service -> Method; each level's name is always a prefix of the prior's, therefore
-	// if we sort lexicographically, we'll always visit parent nodes first.
+func newCache(deps deps, defaults []Option, g *GlobalOptionSet, optionSets ...*OptionSet) (*cache, error) {
+	// init the tree of Global, Package, Service, and Method option sets (and of Host and Path)
+	// Options copy from Global -> Package -> Service -> Method; each level's name is always a prefix of the next's,
+	// therefore if we sort by length, we'll always visit parent nodes first.
 	var (
 		bc    cache
-		stack []*breaker
-		last  *breaker
+		built []*breaker
 	)
 
-	push := func(b *breaker) {
-		stack = append(stack, b)
-		last = b
-	}
-
-	popAndInit := func() {
-		bc.m.Store(last.Key, last)
-		go last.run()
-
-		stack = stack[:len(stack)-1]
-
-		if len(stack) > 0 {
-			last = stack[len(stack)-1]
+	// set aside the pattern option sets, which configure breakers only as they're resolved -- except for the services
+	// of Method option sets, which must be in place before their methods
+	exact := make([]*OptionSet, 0, len(optionSets)+1)
+	for _, os := range optionSets {
+		if os.pattern != nil {
+			bc.patterns = append(bc.patterns, os)
+		} else {
+			exact = append(exact, os)
 		}
 	}
+	if err := validatePatterns(bc.patterns); err != nil {
+		return nil, err
+	}
+	optionSets = append(exact, servicePatterns(exact, bc.patterns)...)
 
 	// add in the global option set which is the defaults + explicit globals
 	optionSets = append(optionSets, &OptionSet{options: append(defaults, g.options...)})
 
 	// we'll traverse the tree according to the names -- global will be first, etc.
-	sort.Slice(optionSets, func(i, j int) bool {
-		return optionSets[i].key.Name < optionSets[j].key.Name
+	sort.SliceStable(optionSets, func(i, j int) bool {
+		return len(optionSets[i].key.Name) < len(optionSets[j].key.Name)
 	})
 
 	seen := make(map[Key]struct{}, len(optionSets))
@@ -67,26 +77,49 @@ func newCache(deps deps, defaults []Option, g *GlobalOptionSet, optionSets ...*O
 		seen[os.key] = struct{}{}
 
 		var base settings
-		if last != nil {
-			for !strings.HasPrefix(os.key.Name, last.Key.Name) { // ascend tree
-				popAndInit()
+		// the longest name of those which can parent ours is the direct parent
+		for i := len(built) - 1; i >= 0; i-- {
+			if os.key.childOf(built[i].Key) {
+				base = built[i].settings
+				break
 			}
-			// last has the closest prefix of our node -- i.e. is the direct parent
-			base = last.settings
 		}
 		for _, o := range os.options {
 			o(&base)
 		}
-		push(newBreaker(os.key, deps, base))
+		built = append(built, newBreaker(os.key, deps, base))
 	}
 
-	for len(stack) > 0 {
-		popAndInit()
+	for _, b := range built {
+		bc.m.Store(b.Key, b)
+		b.start()
 	}
 
-	bc.global = last // global is always last
+	bc.global = built[0] // global is always first
+
+	go func() {
+		<-deps.closeCh
+		bc.closeMu.Lock()
+		onClose := bc.onClose
+		bc.closeMu.Unlock()
+		for _, f := range onClose {
+			f()
+		}
+		deps.sched.stop()
+		bc.m.Range(func(_, v interface{}) bool {
+			v.(*breaker).stop()
+			return true
+		})
+	}()
 
-	return &bc
+	return &bc, nil
+}
+
+// beforeClose registers f to be run once ctx is done, while the breakers are still running
+func (bc *cache) beforeClose(f func()) {
+	bc.closeMu.Lock()
+	defer bc.closeMu.Unlock()
+	bc.onClose = append(bc.onClose, f)
 }
 
 func (bc *cache) resolve(method string, opts []grpc.CallOption) *breaker {
@@ -104,14 +137,25 @@ func (bc *cache) resolve(method string, opts []grpc.CallOption) *breaker {
 		// init call site by loading method
 		parent := bc.resolve(method, nil)
 
+		if max := bc.global.maxCallSites; max > 0 && atomic.LoadInt64(&bc.callSites) >= int64(max) {
+			return parent // too many call sites; rather than grow without bound, share the parent's breaker
+		}
+
 		cp := parent.settings
 		for _, o := range c.optionSet.options {
 			o(&cp)
 		}
 
-		b, loaded := bc.loadOrStore(c.optionSet.key, newBreaker(c.optionSet.key, parent.deps, cp))
+		nb := newBreaker(c.optionSet.key, parent.deps, cp)
+		nb.onIdle = func() {
+			bc.m.Delete(nb.Key)
+			atomic.AddInt64(&bc.callSites, -1)
+		}
+
+		b, loaded := bc.loadOrStore(c.optionSet.key, nb)
 		if !loaded {
-			go b.run()
+			atomic.AddInt64(&bc.callSites, 1)
+			b.start()
 		}
 		return b
 	}
@@ -128,17 +172,51 @@ func (bc *cache) resolve(method string, opts []grpc.CallOption) *breaker {
 	}
 
 	svcKey := Key{Type: BreakerService, Name: method[:idx]}
-	if s, ok := bc.load(svcKey); ok {
-		// forward to method for next time
-		b, _ := bc.loadOrStore(methodKey, s)
-		return b
+	s, ok := bc.load(svcKey)
+	if !ok {
+		s, ok = bc.matchPattern(svcKey, bc.resolvePackage(svcKey.Name), true)
 	}
 
-	b, _ := bc.loadOrStore(svcKey, bc.global)
-	b, _ = bc.loadOrStore(methodKey, b)
+	// forward to method for next time, unless the service is only sharing its parent's breaker for now
+	b, _ := bc.matchPattern(methodKey, s, ok)
 	return b
 }
 
+// resolvePackage returns the breaker of the innermost package of the service with a Package option set, or otherwise
+// the global breaker
+func (bc *cache) resolvePackage(service string) *breaker {
+	name := service
+	for i := strings.LastIndex(name, "."); i > 0; i = strings.LastIndex(name, ".") {
+		name = name[:i]
+		if b, ok := bc.load(Key{Type: BreakerPackage, Name: name}); ok {
+			return b
+		}
+	}
+	return bc.global
+}
+
+// each calls f once for every breaker in the cache, including partitions
+func (bc *cache) each(f func(b *breaker)) {
+	seen := make(map[*breaker]bool)
+	visit := func(b *breaker) {
+		if !seen[b] { // e.g. a service breaker is stored under the keys of its methods as well
+			seen[b] = true
+			f(b)
+		}
+	}
+
+	bc.m.Range(func(_, v interface{}) bool {
+		b := v.(*breaker)
+		visit(b)
+		if b.partitions != nil {
+			for _, p := range b.partitions.all() {
+				visit(p)
+			}
+		}
+		return true
+	})
+}
+
 func (bc *cache) load(key Key) (*breaker, bool) {
 	v, ok := bc.m.Load(key)
 	br, _ := v.(*breaker)
@@ -152,14 +230,42 @@ func (bc *cache) loadOrStore(key Key, br *breaker) (*breaker, bool) {
 
 // Key is the unique identifier of a breaker in grpcbreaker
 type Key struct {
-	Type BreakerType
-	Name string
+	Type      BreakerType
+	Name      string
+	Partition string // set only for the child breakers created by KeyFunc
+}
+
+// childOf reports whether a breaker with this key inherits the options of one with the given key, given that both are
+// configured: Global parents all; a Package parents the Packages, Services and Methods within it; a Service its
+// Methods; and a Host or Path the Paths beneath it. Names only nest at the boundaries of their segments, so that
+// "/foo" parents "/foo.Svc" but not "/foobar.Svc".
+func (k Key) childOf(p Key) bool {
+	if p.Type == BreakerGlobal {
+		return k.Type != BreakerGlobal
+	}
+	if len(k.Name) <= len(p.Name) || !strings.HasPrefix(k.Name, p.Name) {
+		return false
+	}
+
+	switch sep := k.Name[len(p.Name)]; p.Type {
+	case BreakerPackage:
+		return sep == '.' && (k.Type == BreakerPackage || k.Type == BreakerService || k.Type == BreakerMethod)
+	case BreakerService:
+		return sep == '/' && k.Type == BreakerMethod
+	case BreakerHost, BreakerPath:
+		return sep == '/' && k.Type == BreakerPath
+	}
+	return false
 }
 
 // String returns a friendly representation of the identifier
 func (k Key) String() string {
-	if k.Name == "" {
+	switch {
+	case k.Partition != "":
+		return fmt.Sprintf("%v|%v|%v", k.Type, k.Name, k.Partition)
+	case k.Name != "":
+		return fmt.Sprintf("%v|%v", k.Type, k.Name)
+	default:
 		return k.Type.String()
 	}
-	return fmt.Sprintf("%v|%v", k.Type, k.Name)
 }
@@ -1,34 +1,43 @@
 package grpcbreaker
 
 import (
+	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jwilner/grpcbreaker/internal/clock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func Test_cache_resolve(t *testing.T) {
 	ch := make(chan struct{})
+	ctx := context.Background()
 	defer func() { close(ch) }()
 
 	type invok struct {
 		method string
 		opts   []grpc.CallOption
 
-		// note that we can't actually compare the 'predicate' function here, so instead we use the sentinel error
-		// mechanism -- if not null, both functions should match the sentinel error
+		// note that we can't actually compare the classifiers here, so instead we use the sentinel error mechanism --
+		// if not null, both classifiers should classify the sentinel error the same way
 		expectedKey      Key
 		expectedSettings settings
 		sentinelError    error
 	}
 
 	sentinelErr := errors.New("foo")
-	pred := func(err error) bool { return err == sentinelErr }
+	pred := predicateClassifier(func(err error) bool { return err == sentinelErr })
 
 	testCache := func(g *GlobalOptionSet, optionSets ...*OptionSet) *cache {
-		return newCache(deps{closeCh: ch}, nil, g, optionSets...)
+		bc, err := newCache(newDeps(ch, nil, clock.Real{}), nil, g, optionSets...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bc
 	}
 
 	for _, tt := range []struct {
@@ -38,12 +47,12 @@ func Test_cache_resolve(t *testing.T) {
 	}{
 		{
 			name:  "just global",
-			cache: testCache(Global(ResetTimeout(10*time.Minute), Predicate(pred))),
+			cache: testCache(Global(ResetTimeout(10*time.Minute), Classify(pred))),
 			invocations: []invok{
 				{
 					method:           "foo/Get",
 					expectedKey:      Key{Type: BreakerGlobal},
-					expectedSettings: settings{reset: 10 * time.Minute, predicate: pred},
+					expectedSettings: settings{reset: 10 * time.Minute, classifier: pred},
 					sentinelError:    sentinelErr,
 				},
 			},
@@ -51,24 +60,24 @@ func Test_cache_resolve(t *testing.T) {
 		{
 			name: "service level",
 			cache: testCache(
-				Global(ResetTimeout(10*time.Minute), Predicate(pred)),
+				Global(ResetTimeout(10*time.Minute), Classify(pred)),
 				Service("foo", FailThreshold(10)),
 			),
 			invocations: []invok{
 				{
 					method:           "foo/Get",
 					expectedKey:      Key{Type: BreakerService, Name: "foo"},
-					expectedSettings: settings{reset: 10 * time.Minute, failThreshold: 10, predicate: pred},
+					expectedSettings: settings{reset: 10 * time.Minute, failThreshold: 10, classifier: pred},
 				},
 				{
 					method:           "foo/Create",
 					expectedKey:      Key{Type: BreakerService, Name: "foo"},
-					expectedSettings: settings{reset: 10 * time.Minute, failThreshold: 10, predicate: pred},
+					expectedSettings: settings{reset: 10 * time.Minute, failThreshold: 10, classifier: pred},
 				},
 				{
 					method:           "notfoo/blah",
 					expectedKey:      Key{Type: BreakerGlobal},
-					expectedSettings: settings{reset: 10 * time.Minute, predicate: pred},
+					expectedSettings: settings{reset: 10 * time.Minute, classifier: pred},
 				},
 			},
 		},
@@ -102,7 +111,7 @@ func Test_cache_resolve(t *testing.T) {
 			cache: testCache(
 				Global(ResetTimeout(10*time.Minute)),
 				Service("foo", FailThreshold(10)),
-				Method("foo/Get", ResetThreshold(22), Predicate(pred)),
+				Method("foo/Get", ResetThreshold(22), Classify(pred)),
 			),
 			invocations: []invok{
 				{
@@ -112,7 +121,7 @@ func Test_cache_resolve(t *testing.T) {
 						reset:          10 * time.Minute,
 						failThreshold:  10,
 						resetThreshold: 22,
-						predicate:      pred,
+						classifier:     pred,
 					},
 					sentinelError: sentinelErr,
 				},
@@ -124,7 +133,7 @@ func Test_cache_resolve(t *testing.T) {
 						reset:          10 * time.Minute,
 						failThreshold:  11,
 						resetThreshold: 22,
-						predicate:      pred,
+						classifier:     pred,
 					},
 					sentinelError: sentinelErr,
 				},
@@ -149,21 +158,284 @@ func Test_cache_resolve(t *testing.T) {
 						t.Errorf("expected key %+v but got %+v", i.expectedKey, b.Key)
 					}
 
-					// copy out predicates and then null out refs before comparing settings
-					p, expP := b.settings.predicate, i.expectedSettings.predicate
-					b.settings.predicate, i.expectedSettings.predicate = nil, nil
+					// copy out classifiers and then null out refs before comparing settings
+					c, expC := b.settings.classifier, i.expectedSettings.classifier
+					b.settings.classifier, i.expectedSettings.classifier = nil, nil
 					if !reflect.DeepEqual(b.settings, i.expectedSettings) {
 						t.Errorf("expected settings %+v but got %+v", i.expectedSettings, b.settings)
 					}
-					b.settings.predicate, i.expectedSettings.predicate = p, expP
+					b.settings.classifier, i.expectedSettings.classifier = c, expC
 
-					if (p == nil) != (expP == nil) {
-						t.Errorf("expected predicate nullity to be %v but got %v", expP != nil, p != nil)
-					} else if p != nil && (p(i.sentinelError) != expP(i.sentinelError)) {
-						t.Errorf("expected both predicates to match %v but they did not", i.sentinelError)
+					info := CallInfo{Err: i.sentinelError}
+					if (c == nil) != (expC == nil) {
+						t.Errorf("expected classifier nullity to be %v but got %v", expC != nil, c != nil)
+					} else if c != nil && c.Classify(ctx, info) != expC.Classify(ctx, info) {
+						t.Errorf("expected both classifiers to match %v but they did not", i.sentinelError)
 					}
 				})
 			}
 		})
 	}
 }
+
+func Test_breaker_partition(t *testing.T) {
+	ch := make(chan struct{})
+	defer func() { close(ch) }()
+
+	bc, err := newCache(
+		newDeps(ch, nil, clock.Real{}),
+		nil,
+		Global(),
+		Service("foo", KeyFunc(OutgoingMetadata("tenant")), MaxPartitions(2)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := func(name string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "tenant", name)
+	}
+
+	resolve := func(ctx context.Context, method string) *breaker {
+		return bc.resolve(method, nil).partition(ctx, method, nil)
+	}
+
+	a := resolve(tenant("a"), "foo/Get")
+	if expected := (Key{Type: BreakerService, Name: "foo", Partition: "a"}); a.Key != expected {
+		t.Fatalf("expected key %v but got %v", expected, a.Key)
+	}
+	if a.partitions != nil {
+		t.Fatalf("expected partition not to be partitioned itself")
+	}
+	if b := resolve(tenant("a"), "foo/Create"); b != a {
+		t.Fatalf("expected the same breaker for the same partition")
+	}
+	if b := resolve(context.Background(), "foo/Get"); b.Key != (Key{Type: BreakerService, Name: "foo"}) {
+		t.Fatalf("expected unpartitioned call to use the service breaker but got %v", b.Key)
+	}
+	if b := resolve(tenant("a"), "notfoo/Get"); b.Key != (Key{Type: BreakerGlobal}) {
+		t.Fatalf("expected global breaker not to be partitioned but got %v", b.Key)
+	}
+
+	resolve(tenant("b"), "foo/Get")
+	resolve(tenant("c"), "foo/Get") // evicts a, the least recently used
+
+	if a.load() != stopped {
+		t.Fatalf("expected evicted partition to be stopped")
+	}
+	if b := resolve(tenant("a"), "foo/Get"); b == a {
+		t.Fatalf("expected a new breaker for an evicted partition")
+	}
+}
+
+func Test_cache_evictsIdleCallSites(t *testing.T) {
+	ch := make(chan struct{})
+	defer func() { close(ch) }()
+
+	evs := make(chan Event, 100)
+	clk := clock.NewFake(time.Unix(0, 0))
+	bc, err := newCache(newDeps(ch, evs, clk), nil, Global(IdleTTL(time.Minute), MaxCallSites(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := bc.resolve("foo/Get", []grpc.CallOption{CallSite("bizbaz")})
+	if expected := (Key{Type: BreakerCallSite, Name: "bizbaz"}); b.Key != expected {
+		t.Fatalf("expected key %v but got %v", expected, b.Key)
+	}
+	if other := bc.resolve("foo/Get", []grpc.CallOption{CallSite("other")}); other.Key != (Key{Type: BreakerGlobal}) {
+		t.Fatalf("expected call sites beyond the max to use the parent but got %v", other.Key)
+	}
+
+	clk.Advance(time.Minute - 1)
+	if _, ok := bc.load(b.Key); !ok {
+		t.Fatalf("expected call site not to be evicted before the idle TTL")
+	}
+	clk.Advance(1)
+
+	select {
+	case ev := <-evs:
+		if e, ok := ev.(EvictedEvent); !ok || e.Key != b.Key || !e.LastUsed.Equal(time.Unix(0, 0)) {
+			t.Fatalf("expected eviction of %v but got %+v", b.Key, ev)
+		}
+	default:
+		t.Fatalf("expected an eviction event")
+	}
+
+	if _, ok := bc.load(b.Key); ok {
+		t.Fatalf("expected evicted call site to be removed")
+	}
+	if other := bc.resolve("foo/Get", []grpc.CallOption{CallSite("other")}); other.Key.Type != BreakerCallSite {
+		t.Fatalf("expected eviction to make room for a new call site but got %v", other.Key)
+	}
+}
+
+func Test_cache_patterns(t *testing.T) {
+	ch := make(chan struct{})
+	defer func() { close(ch) }()
+
+	bc, err := newCache(
+		newDeps(ch, nil, clock.Real{}),
+		nil,
+		Global(FailThreshold(1)),
+		ServiceGlob("/billing.v1.*", FailThreshold(2)),
+		Service("/billing.v1.Ledger", FailThreshold(3)),
+		MethodGlob("/billing.v1.*/List*", ResetThreshold(4)),
+		MethodRegexp(`/billing\.v1\.Invoices/(Get|Put)`, ResetThreshold(5)),
+		Method("/billing.v1.Invoices/ListAll", ResetThreshold(6)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		method                        string
+		key                           Key
+		failThreshold, resetThreshold int
+	}{
+		{"/billing.v1.Invoices/Create", Key{Type: BreakerService, Name: "/billing.v1.Invoices"}, 2, 0},
+		// "*" crosses package segments
+		{"/billing.v1.internal.Audit/Create", Key{Type: BreakerService, Name: "/billing.v1.internal.Audit"}, 2, 0},
+		{"/billing.v1.Invoices/ListOpen", Key{Type: BreakerMethod, Name: "/billing.v1.Invoices/ListOpen"}, 2, 4},
+		{"/billing.v1.Invoices/Get", Key{Type: BreakerMethod, Name: "/billing.v1.Invoices/Get"}, 2, 5},
+		{"/billing.v1.Invoices/ListAll", Key{Type: BreakerMethod, Name: "/billing.v1.Invoices/ListAll"}, 2, 6},
+		{"/billing.v1.Ledger/ListEntries", Key{Type: BreakerMethod, Name: "/billing.v1.Ledger/ListEntries"}, 3, 4},
+		{"/billing.v2.Invoices/ListOpen", Key{Type: BreakerGlobal}, 1, 0},
+	} {
+		t.Run(tt.method, func(t *testing.T) {
+			b := bc.resolve(tt.method, nil)
+			if b.Key != tt.key {
+				t.Fatalf("expected key %v but got %v", tt.key, b.Key)
+			}
+			if b.failThreshold != tt.failThreshold || b.resetThreshold != tt.resetThreshold {
+				t.Fatalf(
+					"expected thresholds %d, %d but got %d, %d",
+					tt.failThreshold, tt.resetThreshold, b.failThreshold, b.resetThreshold,
+				)
+			}
+			if again := bc.resolve(tt.method, nil); again != b {
+				t.Fatalf("expected the same breaker on resolving again")
+			}
+		})
+	}
+}
+
+func Test_cache_evictsIdlePatternBreakers(t *testing.T) {
+	ch := make(chan struct{})
+	defer func() { close(ch) }()
+
+	clk := clock.NewFake(time.Unix(0, 0))
+	bc, err := newCache(
+		newDeps(ch, nil, clk),
+		nil,
+		Global(IdleTTL(time.Minute), MaxPatternBreakers(2)),
+		ServiceGlob("/dyn.*", FailThreshold(2)),
+		MethodRegexp(`/any\..*`, FailThreshold(3)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := bc.resolve("/dyn.Svc/Get", nil)
+	if expected := (Key{Type: BreakerService, Name: "/dyn.Svc"}); svc.Key != expected {
+		t.Fatalf("expected key %v but got %v", expected, svc.Key)
+	}
+	first := bc.resolve("/any.Svc/First", nil)
+	if expected := (Key{Type: BreakerMethod, Name: "/any.Svc/First"}); first.Key != expected {
+		t.Fatalf("expected key %v but got %v", expected, first.Key)
+	}
+	if other := bc.resolve("/any.Svc/Other", nil); other.Key != (Key{Type: BreakerGlobal}) {
+		t.Fatalf("expected names beyond the max to use the parent but got %v", other.Key)
+	}
+	if _, ok := bc.load(Key{Type: BreakerMethod, Name: "/any.Svc/Other"}); ok {
+		t.Fatal("expected a name beyond the max not to be forwarded to the parent for good")
+	}
+
+	clk.Advance(time.Minute)
+	for _, k := range []Key{svc.Key, {Type: BreakerMethod, Name: "/dyn.Svc/Get"}, first.Key} {
+		if _, ok := bc.load(k); ok {
+			t.Fatalf("expected %v to be evicted along with the keys forwarded to it", k)
+		}
+	}
+
+	if other := bc.resolve("/any.Svc/Other", nil); other.Key.Type != BreakerMethod || other.failThreshold != 3 {
+		t.Fatalf("expected eviction to make room for a new breaker but got %v", other.Key)
+	}
+	if again := bc.resolve("/dyn.Svc/Get", nil); again == svc || again.Key != svc.Key {
+		t.Fatalf("expected a new breaker for %v", svc.Key)
+	}
+}
+
+func Test_validatePatterns(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		sets     []*OptionSet
+		expected string
+	}{
+		{"disjoint globs", []*OptionSet{MethodGlob("/a.*/List*"), MethodGlob("/a.*/Get*")}, ""},
+		{"overlapping globs", []*OptionSet{MethodGlob("/a.*/List*"), MethodGlob("/a.S/*")}, "overlap"},
+		{"types don't overlap", []*OptionSet{ServiceGlob("/a.*"), MethodGlob("/a.*")}, ""},
+		{"glob and regexp", []*OptionSet{ServiceGlob("/a.*"), ServiceRegexp(`/a\.(B|C)`)}, "overlap"},
+		{"stars stop at slashes", []*OptionSet{ServiceGlob("/a*"), ServiceRegexp(`/a.*/b`)}, ""},
+		{"case folding", []*OptionSet{ServiceRegexp(`(?i)/FOO`), ServiceGlob("/f?o")}, "overlap"},
+		{"disjoint regexps", []*OptionSet{ServiceRegexp(`/[a-m].*`), ServiceRegexp(`/[n-z].*`)}, ""},
+		{"invalid", []*OptionSet{ServiceRegexp(`/(`)}, "missing closing )"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePatterns(tt.sets)
+			switch {
+			case tt.expected == "" && err != nil:
+				t.Fatalf("expected no error but got %v", err)
+			case tt.expected != "" && (err == nil || !strings.Contains(err.Error(), tt.expected)):
+				t.Fatalf("expected an error containing %q but got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func Test_cache_packages(t *testing.T) {
+	ch := make(chan struct{})
+	defer func() { close(ch) }()
+
+	bc, err := newCache(
+		newDeps(ch, nil, clock.Real{}),
+		nil,
+		Global(FailThreshold(1)),
+		Package("/bil", ResetThreshold(8)),
+		Package("/billing", FailThreshold(2)),
+		Package("/billing.v1", FailThreshold(3)),
+		Service("/billing.v1.Invoices", ResetThreshold(4)),
+		Method("/billing.v1.Ledger/Get", ResetThreshold(5)),
+		Service("/bill", FailThreshold(9)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		method                        string
+		key                           Key
+		failThreshold, resetThreshold int
+	}{
+		{"/billing.v1.Invoices/Get", Key{Type: BreakerService, Name: "/billing.v1.Invoices"}, 3, 4},
+		{"/billing.v1.Ledger/List", Key{Type: BreakerPackage, Name: "/billing.v1"}, 3, 0},
+		{"/billing.v1.Ledger/Get", Key{Type: BreakerMethod, Name: "/billing.v1.Ledger/Get"}, 3, 5},
+		{"/billing.v2.Ledger/Get", Key{Type: BreakerPackage, Name: "/billing"}, 2, 0},
+		{"/bil.Ledger/Get", Key{Type: BreakerPackage, Name: "/bil"}, 1, 8},
+		{"/bill/Get", Key{Type: BreakerService, Name: "/bill"}, 9, 0},
+		{"/billingx.Ledger/Get", Key{Type: BreakerGlobal}, 1, 0},
+	} {
+		t.Run(tt.method, func(t *testing.T) {
+			b := bc.resolve(tt.method, nil)
+			if b.Key != tt.key {
+				t.Fatalf("expected key %v but got %v", tt.key, b.Key)
+			}
+			if b.failThreshold != tt.failThreshold || b.resetThreshold != tt.resetThreshold {
+				t.Fatalf(
+					"expected thresholds %d, %d but got %d, %d",
+					tt.failThreshold, tt.resetThreshold, b.failThreshold, b.resetThreshold,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package grpcbreaker
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Cause is a failure which counted toward tripping a breaker
+type Cause struct {
+	Method  string
+	Code    codes.Code
+	Message string
+	Time    time.Time
+}
+
+type causeEntry struct {
+	Cause
+	seq uint64
+}
+
+func (b *breaker) newCauses() []unsafe.Pointer {
+	if b.maxCauses <= 0 {
+		return nil
+	}
+	return make([]unsafe.Pointer, b.maxCauses)
+}
+
+// recordCause retains the failure against the generation in which its call started, redacting it first
+func (b *breaker) recordCause(g *generation, info CallInfo) {
+	if len(g.causes) == 0 {
+		return
+	}
+
+	s := status.Convert(info.Err)
+	c := Cause{Method: info.Method, Code: s.Code(), Message: s.Message(), Time: b.clock.Now()}
+	if b.redact != nil {
+		c = b.redact(c)
+	}
+
+	seq := atomic.AddUint64(&g.causeSeq, 1)
+	atomic.StorePointer(&g.causes[(seq-1)%uint64(len(g.causes))], unsafe.Pointer(&causeEntry{c, seq}))
+}
+
+// recentCauses returns the causes retained by the generation, oldest first
+func (g *generation) recentCauses() []Cause {
+	var entries []*causeEntry
+	for i := range g.causes {
+		if e := (*causeEntry)(atomic.LoadPointer(&g.causes[i])); e != nil {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+
+	causes := make([]Cause, len(entries))
+	for i, e := range entries {
+		causes[i] = e.Cause
+	}
+	return causes
+}
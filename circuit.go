@@ -0,0 +1,72 @@
+package grpcbreaker
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Circuit is a standalone breaker protecting arbitrary calls -- e.g. to Kafka, SQL, or third party SDKs -- with the
+// same semantics and events as the breakers of the gRPC interceptor
+type Circuit struct {
+	Events <-chan Event
+
+	b *breaker
+}
+
+// NewCircuit returns a Circuit with the given name and options, stopped once ctx is done. Options configuring a
+// whole Breaker, such as MaxCallSites, Persist and Share, have no effect. Calls are identified to KeyFunc and
+// classifiers by the circuit's name in place of a method.
+func NewCircuit(ctx context.Context, name string, opts ...Option) *Circuit {
+	var s settings
+	for _, o := range append(defaultOptions(), opts...) {
+		o(&s)
+	}
+
+	events := make(chan Event, 100)
+	deps := newDeps(ctx.Done(), events, s.globalClock)
+	b := newBreaker(Key{Type: BreakerCircuit, Name: name}, deps, s)
+	b.start()
+
+	go func() {
+		<-ctx.Done()
+		deps.sched.stop()
+		b.stop()
+	}()
+
+	return &Circuit{Events: events, b: b}
+}
+
+// Execute calls f unless the circuit is open, in which case it returns ErrBreakerOpen, and records its outcome
+func (c *Circuit) Execute(ctx context.Context, f func(ctx context.Context) error) error {
+	return c.b.partition(ctx, c.b.Name, nil).call(ctx, c.b.Name, func(ctx context.Context) (metadata.MD, error) {
+		return nil, f(ctx)
+	})
+}
+
+// Snapshot returns the state of the circuit, followed by that of any partitions
+func (c *Circuit) Snapshot() []Snapshot {
+	var snaps []Snapshot
+	if s, ok := c.b.snapshot(); ok {
+		snaps = append(snaps, s)
+	}
+	if c.b.partitions != nil {
+		for _, p := range c.b.partitions.all() {
+			if s, ok := p.snapshot(); ok {
+				snaps = append(snaps, s)
+			}
+		}
+	}
+	return snaps
+}
+
+// History returns the recent transitions and sheds of the circuit itself, or false if HistorySize is zero
+func (c *Circuit) History() (History, bool) {
+	return c.b.recentHistory()
+}
+
+// Subscribe returns a channel receiving every event published after the call, in addition to Events, until ctx is
+// done
+func (c *Circuit) Subscribe(ctx context.Context, buffer int) <-chan Event {
+	return c.b.subs.add(ctx, buffer, nil)
+}
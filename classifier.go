@@ -0,0 +1,59 @@
+package grpcbreaker
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// CallInfo describes a completed call for the purposes of classification
+type CallInfo struct {
+	Method  string
+	Err     error
+	Elapsed time.Duration
+	Trailer metadata.MD
+}
+
+// Classification is a Classifier's verdict on a single call
+type Classification struct {
+	Outcome Outcome
+	// Weight is how much a Failure counts toward the FailThreshold; zero is treated as a full failure
+	Weight float64
+	// RetryAfter is the least time the breaker stays open should this Failure open it; it defaults to that of the
+	// call's error, if the error has a RetryAfter() time.Duration method like StatusError's
+	RetryAfter time.Duration
+}
+
+func (c Classification) weight() float64 {
+	if c.Outcome != Failure {
+		return 0
+	}
+	if c.Weight == 0 {
+		return 1
+	}
+	return c.Weight
+}
+
+// Classifier decides whether a call succeeded, failed, or should be ignored by the breaker
+type Classifier interface {
+	Classify(ctx context.Context, info CallInfo) Classification
+}
+
+// ClassifierFunc adapts a function to the Classifier interface
+type ClassifierFunc func(ctx context.Context, info CallInfo) Classification
+
+// Classify calls f
+func (f ClassifierFunc) Classify(ctx context.Context, info CallInfo) Classification {
+	return f(ctx, info)
+}
+
+// predicateClassifier treats any error matching the predicate as a full failure and everything else as a success
+type predicateClassifier func(error) bool
+
+func (p predicateClassifier) Classify(_ context.Context, info CallInfo) Classification {
+	if info.Err != nil && p(info.Err) {
+		return Classification{Outcome: Failure}
+	}
+	return Classification{Outcome: Success}
+}
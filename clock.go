@@ -0,0 +1,9 @@
+package grpcbreaker
+
+import "github.com/jwilner/grpcbreaker/internal/clock"
+
+// Clock tells the time and creates timers for the breakers; see WithClock and grpcbreakertest.FakeClock
+type Clock = clock.Clock
+
+// Timer is a timer created by a Clock
+type Timer = clock.Timer
@@ -0,0 +1,154 @@
+// Command grpcbreaker-sim replays a JSONL trace of recorded gRPC traffic through candidate grpcbreaker policies and
+// compares how each would have behaved.
+//
+// Usage:
+//
+//	grpcbreaker-sim [-trace file] -policy spec [-policy spec ...]
+//
+// A policy spec is a name followed by comma separated settings, e.g.
+//
+//	-policy 'tight:fail=3,reset=10s,codes=UNAVAILABLE|DEADLINE_EXCEEDED'
+//
+// The settings are fail (FailThreshold), reset (ResetTimeout), passes (ResetThreshold), and codes, the status codes
+// counted as failures (by default, every code but OK). The trace is read from stdin unless -trace is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/sim"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func main() {
+	var policies policyFlags
+	tracePath := flag.String("trace", "", "path to the JSONL trace; defaults to stdin")
+	flag.Var(&policies, "policy", "a candidate policy as name:setting=value,...; may be repeated")
+	flag.Parse()
+
+	if len(policies) == 0 {
+		log.Fatal("at least one -policy is required")
+	}
+
+	var in io.Reader = os.Stdin
+	if *tracePath != "" {
+		f, err := os.Open(*tracePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		in = f
+	}
+
+	reports, err := sim.Simulate(in, policies...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "POLICY\tCALLS\tUNREPLAYED\tSHED\tSHED OK\tTRIPS\tFALSE TRIPS\tMEAN TIME TO TRIP")
+	for _, r := range reports {
+		_, _ = fmt.Fprintf(
+			w,
+			"%v\t%d\t%d\t%d\t%d\t%d\t%d\t%v\n",
+			r.Policy, r.Calls, r.Unreplayed, r.Shed, r.ShedSuccesses, r.Trips, r.FalseTrips, r.MeanTimeToTrip,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type policyFlags []sim.Policy
+
+func (p *policyFlags) String() string {
+	names := make([]string, 0, len(*p))
+	for _, pol := range *p {
+		names = append(names, pol.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func (p *policyFlags) Set(spec string) error {
+	pol, err := parsePolicy(spec)
+	if err != nil {
+		return err
+	}
+	*p = append(*p, pol)
+	return nil
+}
+
+func parsePolicy(spec string) (sim.Policy, error) {
+	name, settings := spec, ""
+	if idx := strings.Index(spec, ":"); idx >= 0 {
+		name, settings = spec[:idx], spec[idx+1:]
+	}
+	if name == "" {
+		return sim.Policy{}, fmt.Errorf("policy %q has no name", spec)
+	}
+
+	var opts []grpcbreaker.Option
+	for _, setting := range strings.Split(settings, ",") {
+		if setting == "" {
+			continue
+		}
+		kv := strings.SplitN(setting, "=", 2)
+		if len(kv) != 2 {
+			return sim.Policy{}, fmt.Errorf("policy %q: setting %q is not key=value", name, setting)
+		}
+
+		opt, err := parseSetting(kv[0], kv[1])
+		if err != nil {
+			return sim.Policy{}, fmt.Errorf("policy %q: %w", name, err)
+		}
+		opts = append(opts, opt)
+	}
+
+	return sim.Policy{Name: name, Global: grpcbreaker.Global(opts...)}, nil
+}
+
+func parseSetting(key, val string) (grpcbreaker.Option, error) {
+	switch key {
+	case "fail", "passes":
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", key, err)
+		}
+		if key == "fail" {
+			return grpcbreaker.FailThreshold(n), nil
+		}
+		return grpcbreaker.ResetThreshold(n), nil
+
+	case "reset":
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", key, err)
+		}
+		return grpcbreaker.ResetTimeout(d), nil
+
+	case "codes":
+		failures := make(map[codes.Code]bool)
+		for _, name := range strings.Split(val, "|") {
+			var c codes.Code
+			if err := c.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(name)))); err != nil {
+				return nil, fmt.Errorf("%v: %w", key, err)
+			}
+			failures[c] = true
+		}
+		return grpcbreaker.Predicate(func(err error) bool { return failures[status.Code(err)] }), nil
+	}
+
+	return nil, fmt.Errorf("unknown setting %q", key)
+}
@@ -0,0 +1,235 @@
+// Command grpcbreakerctl inspects and controls the breakers of a running process through the handler of the admin
+// package.
+//
+// Usage:
+//
+//	grpcbreakerctl [-addr url] list [-type type] [-name prefix] [-o table|json] [-watch interval]
+//	grpcbreakerctl [-addr url] tail [-type type] [-name prefix] [-o table|json]
+//	grpcbreakerctl [-addr url] open|close|halfopen -type type [-name name] [-partition partition]
+//
+// Types are global, package, service, method, callsite, host and path. open forces a breaker open until its reset
+// timeout, close closes it clearing its counters, and halfopen ends an open breaker's reset timeout early so that it
+// starts probing; halfopen doesn't reset the breaker, which close does.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwilner/grpcbreaker/admin"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	addr := flag.String("addr", "http://localhost:8080/debug/grpcbreaker", "the URL at which the admin handler is served")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "usage: grpcbreakerctl [-addr url] list|tail|open|close|halfopen [flags]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c := client{strings.TrimSuffix(*addr, "/")}
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+
+	var err error
+	switch cmd {
+	case "list":
+		err = c.list(args)
+	case "tail":
+		err = c.tail(args)
+	case "open", "close", "halfopen":
+		err = c.override(cmd, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type client struct {
+	addr string
+}
+
+// filterFlags registers the flags which filter breakers and events
+func filterFlags(fs *flag.FlagSet) func() url.Values {
+	typ := fs.String("type", "", "only breakers of this type")
+	name := fs.String("name", "", "only breakers whose names have this prefix")
+	return func() url.Values {
+		q := url.Values{}
+		if *typ != "" {
+			q.Set("type", *typ)
+		}
+		if *name != "" {
+			q.Set("name", *name)
+		}
+		return q
+	}
+}
+
+func (c client) list(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	query := filterFlags(fs)
+	output := fs.String("o", "table", "the output format, table or json")
+	watch := fs.Duration("watch", 0, "if set, list again at this interval until interrupted")
+	_ = fs.Parse(args)
+
+	for {
+		breakers, err := c.breakers(query())
+		if err != nil {
+			return err
+		}
+
+		switch *output {
+		case "json":
+			err = json.NewEncoder(os.Stdout).Encode(breakers)
+		default:
+			if *watch > 0 {
+				fmt.Print("\033[H\033[2J") // clear the screen
+			}
+			err = printBreakers(breakers)
+		}
+		if err != nil || *watch <= 0 {
+			return err
+		}
+		time.Sleep(*watch)
+	}
+}
+
+func (c client) breakers(query url.Values) ([]admin.Breaker, error) {
+	resp, err := http.Get(c.addr + "/breakers?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	var breakers []admin.Breaker
+	err = json.NewDecoder(resp.Body).Decode(&breakers)
+	return breakers, err
+}
+
+func printBreakers(breakers []admin.Breaker) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TYPE\tNAME\tPARTITION\tSTATE\tGEN\tFAILS\tSCORE\tPASSES\tIGNORED\tLAST FAIL\tRESET AT")
+	for _, b := range breakers {
+		_, _ = fmt.Fprintf(
+			w,
+			"%v\t%v\t%v\t%v\t%d\t%d\t%g\t%d\t%d\t%v\t%v\n",
+			b.Type, b.Name, b.Partition, b.State, b.Gen, b.Fails, b.FailScore, b.Passes, b.Ignored,
+			formatTime(b.LastFail), formatTime(b.ResetMoment),
+		)
+	}
+	return w.Flush()
+}
+
+func (c client) tail(args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ExitOnError)
+	query := filterFlags(fs)
+	output := fs.String("o", "table", "the output format, table or json")
+	_ = fs.Parse(args)
+
+	resp, err := http.Get(c.addr + "/events?" + query().Encode())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var ev admin.Event
+		switch err := dec.Decode(&ev); {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		if *output == "json" {
+			if err := json.NewEncoder(os.Stdout).Encode(ev); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := strings.TrimRight(strings.Join([]string{ev.Type, ev.Name, ev.Partition}, " "), " ")
+		switch ev.Kind {
+		case "state":
+			fmt.Printf("%v  %v  %v -> %v (gen %d, fails %d, passes %d)\n",
+				ev.Time.Format(time.RFC3339Nano), key, ev.Old, ev.New, ev.Gen, ev.Fails, ev.Passes)
+		case "shed":
+			fmt.Printf("%v  %v  shed while %v\n", ev.Time.Format(time.RFC3339Nano), key, ev.State)
+		default:
+			fmt.Printf("%v  %v  %v\n", ev.Time.Format(time.RFC3339Nano), key, ev.Kind)
+		}
+	}
+}
+
+func (c client) override(cmd string, args []string) error {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	typ := fs.String("type", "", "the type of the breaker")
+	name := fs.String("name", "", "the name of the breaker")
+	partition := fs.String("partition", "", "the partition of the breaker, if any")
+	_ = fs.Parse(args)
+	if *typ == "" {
+		return fmt.Errorf("%v: -type is required", cmd)
+	}
+
+	state := map[string]string{"open": "open", "close": "closed", "halfopen": "halfopen"}[cmd]
+	body, err := json.Marshal(admin.Override{
+		Key:   admin.Key{Type: *typ, Name: *name, Partition: *partition},
+		State: state,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(c.addr+"/override", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	return checkStatus(resp)
+}
+
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	return fmt.Errorf("%v: %v", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,197 @@
+// Command protoc-gen-grpcbreaker generates breaker policy from the options declared in grpcbreaker/options.proto, so
+// that policy can live next to the API definition:
+//
+//	service Payments {
+//	  option (grpcbreaker.service_policy) = {fail_threshold: 5, reset_timeout: {seconds: 30}};
+//	  rpc Charge(ChargeRequest) returns (ChargeResponse) {
+//	    option (grpcbreaker.method_policy) = {failure_codes: ["UNAVAILABLE", "DEADLINE_EXCEEDED"]};
+//	  }
+//	}
+//
+// For each annotated service, it generates a function returning the service's and methods' option sets for
+// grpcbreaker.New, e.g. PaymentsBreakerPolicy, alongside PaymentsFallbackable and PaymentsIdempotent, the sets of
+// methods declared to be so. Run it as protoc --grpcbreaker_out=paths=source_relative:. foo.proto.
+//
+// The options are extensions numbered 52100, which is from the range 50000-99999 that descriptor.proto leaves to
+// organizations' own options, rather than a number registered in protobuf's global extension registry; see
+// grpcbreaker/options.proto.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jwilner/grpcbreaker/optionspb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	grpcbreakerPackage = protogen.GoImportPath("github.com/jwilner/grpcbreaker")
+	codesPackage       = protogen.GoImportPath("google.golang.org/grpc/codes")
+	statusPackage      = protogen.GoImportPath("google.golang.org/grpc/status")
+	timePackage        = protogen.GoImportPath("time")
+)
+
+func main() {
+	protogen.Options{}.Run(run)
+}
+
+func run(gen *protogen.Plugin) error {
+	for _, f := range gen.Files {
+		if f.Generate {
+			if err := generateFile(gen, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateFile writes the policy of the file's annotated services, if it has any
+func generateFile(gen *protogen.Plugin, file *protogen.File) error {
+	var services []*protogen.Service
+	for _, s := range file.Services {
+		if hasPolicy(s) {
+			services = append(services, s)
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+"_breaker.pb.go", file.GoImportPath)
+	g.P("// Code generated by protoc-gen-grpcbreaker. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+
+	for _, s := range services {
+		if err := generateService(g, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasPolicy(s *protogen.Service) bool {
+	if servicePolicy(s) != nil {
+		return true
+	}
+	for _, m := range s.Methods {
+		if methodPolicy(m) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func generateService(g *protogen.GeneratedFile, s *protogen.Service) error {
+	svcName := "/" + string(s.Desc.FullName())
+	svcPolicy := servicePolicy(s)
+
+	g.P()
+	g.P("// ", s.GoName, "BreakerPolicy returns the option sets of the breaker policy declared on ", s.GoName,
+		" and its methods")
+	g.P("func ", s.GoName, "BreakerPolicy() []*", grpcbreakerPackage.Ident("OptionSet"), " {")
+	g.P("return []*", grpcbreakerPackage.Ident("OptionSet"), "{")
+	if svcPolicy != nil {
+		if err := generateOptionSet(g, "Service", svcName, svcPolicy); err != nil {
+			return fmt.Errorf("%v: %w", s.Desc.FullName(), err)
+		}
+	}
+	for _, m := range s.Methods {
+		if p := methodPolicy(m); p != nil {
+			if err := generateOptionSet(g, "Method", svcName+"/"+string(m.Desc.Name()), p); err != nil {
+				return fmt.Errorf("%v: %w", m.Desc.FullName(), err)
+			}
+		}
+	}
+	g.P("}")
+	g.P("}")
+
+	for _, trait := range []struct {
+		name, desc string
+		get        func(*optionspb.Policy) bool
+	}{
+		{"Fallbackable", "fallbackable", (*optionspb.Policy).GetFallbackable},
+		{"Idempotent", "idempotent", (*optionspb.Policy).GetIdempotent},
+	} {
+		g.P()
+		g.P("// ", s.GoName, trait.name, " is the set of ", s.GoName, "'s methods declared ", trait.desc,
+			", by full method name")
+		g.P("var ", s.GoName, trait.name, " = map[string]bool{")
+		for _, m := range s.Methods {
+			if trait.get(svcPolicy) || trait.get(methodPolicy(m)) {
+				g.P(fmt.Sprintf("%q", svcName+"/"+string(m.Desc.Name())), ": true,")
+			}
+		}
+		g.P("}")
+	}
+	return nil
+}
+
+func generateOptionSet(g *protogen.GeneratedFile, constructor, name string, p *optionspb.Policy) error {
+	g.P(grpcbreakerPackage.Ident(constructor), "(")
+	g.P(fmt.Sprintf("%q", name), ",")
+
+	if p.GetFailThreshold() > 0 {
+		g.P(grpcbreakerPackage.Ident("FailThreshold"), "(", p.GetFailThreshold(), "),")
+	}
+
+	if p.GetResetTimeout() != nil {
+		if err := p.GetResetTimeout().CheckValid(); err != nil {
+			return err
+		}
+		d := p.GetResetTimeout().AsDuration()
+		g.P(grpcbreakerPackage.Ident("ResetTimeout"), "(", int64(d), "*", timePackage.Ident("Nanosecond"), "), // ", d)
+	}
+
+	if len(p.GetFailureCodes()) > 0 {
+		idents := make([]string, 0, len(p.GetFailureCodes()))
+		seen := make(map[codes.Code]bool)
+		for _, name := range p.GetFailureCodes() {
+			var c codes.Code
+			if err := json.Unmarshal([]byte(fmt.Sprintf("%q", name)), &c); err != nil {
+				return fmt.Errorf("unknown failure code %q", name)
+			}
+			if seen[c] { // a duplicate case wouldn't compile
+				continue
+			}
+			seen[c] = true
+			idents = append(idents, g.QualifiedGoIdent(codesPackage.Ident(c.String())))
+		}
+		g.P(grpcbreakerPackage.Ident("Predicate"), "(func(err error) bool {")
+		g.P("switch ", statusPackage.Ident("Code"), "(err) {")
+		g.P("case ", strings.Join(idents, ", "), ":")
+		g.P("return true")
+		g.P("}")
+		g.P("return false")
+		g.P("}),")
+	}
+
+	g.P("),")
+	return nil
+}
+
+func servicePolicy(s *protogen.Service) *optionspb.Policy {
+	opts, _ := s.Desc.Options().(*descriptorpb.ServiceOptions)
+	return policy(opts, optionspb.E_ServicePolicy)
+}
+
+func methodPolicy(m *protogen.Method) *optionspb.Policy {
+	opts, _ := m.Desc.Options().(*descriptorpb.MethodOptions)
+	return policy(opts, optionspb.E_MethodPolicy)
+}
+
+func policy(opts proto.Message, ext protoreflect.ExtensionType) *optionspb.Policy {
+	if opts == nil || !proto.HasExtension(opts, ext) {
+		return nil
+	}
+	p, _ := proto.GetExtension(opts, ext).(*optionspb.Policy)
+	return p
+}
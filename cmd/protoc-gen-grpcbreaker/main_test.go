@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jwilner/grpcbreaker/optionspb"
+	"github.com/jwilner/grpcbreaker/pbtest"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// generate runs the plugin on pbtest/a.proto, as changed by edit, returning the generated file's content
+func generate(t *testing.T, edit func(*descriptorpb.FileDescriptorProto)) (string, error) {
+	t.Helper()
+
+	a := protodesc.ToFileDescriptorProto(pbtest.File_a_proto)
+	edit(a)
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{a.GetName()},
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			protodesc.ToFileDescriptorProto(durationpb.File_google_protobuf_duration_proto),
+			protodesc.ToFileDescriptorProto(optionspb.File_grpcbreaker_options_proto),
+			a,
+		},
+	}
+
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run(gen); err != nil {
+		return "", err
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatal(resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "a_breaker.pb.go" {
+		t.Fatalf("expected a_breaker.pb.go to be generated but got %v", resp.File)
+	}
+	return resp.File[0].GetContent(), nil
+}
+
+// setMethodCodes replaces the failure codes of SvcA's Get method
+func setMethodCodes(codes ...string) func(*descriptorpb.FileDescriptorProto) {
+	return func(f *descriptorpb.FileDescriptorProto) {
+		opts := f.GetService()[0].GetMethod()[0].GetOptions()
+		proto.SetExtension(opts, optionspb.E_MethodPolicy, &optionspb.Policy{FailureCodes: codes})
+	}
+}
+
+// TestGolden checks the plugin against the file it generated for pbtest, which the module's tests exercise
+func TestGolden(t *testing.T) {
+	got, err := generate(t, func(*descriptorpb.FileDescriptorProto) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile("../../pbtest/a_breaker.pb.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(golden) {
+		t.Fatalf("generated file doesn't match pbtest/a_breaker.pb.go; regenerate it with make -C pbtest:\n%v", got)
+	}
+}
+
+func TestDuplicateCodes(t *testing.T) {
+	got, err := generate(t, setMethodCodes("UNAVAILABLE", "DEADLINE_EXCEEDED", "UNAVAILABLE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// once for the service and once for the method
+	if n := strings.Count(got, "codes.Unavailable"); n != 2 {
+		t.Fatalf("expected codes.Unavailable twice but got it %d times in:\n%v", n, got)
+	}
+}
+
+func TestUnknownCode(t *testing.T) {
+	_, err := generate(t, setMethodCodes("UNAVAILABLE", "NOT_A_CODE"))
+	if err == nil || !strings.Contains(err.Error(), "pbtest.SvcA.Get") || !strings.Contains(err.Error(), "NOT_A_CODE") {
+		t.Fatalf("expected an error naming the method and the code but got %v", err)
+	}
+}
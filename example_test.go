@@ -1,47 +1,30 @@
 package grpcbreaker_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"net"
-	"os"
-	"path"
+	"io"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
 	"github.com/jwilner/grpcbreaker/pbtest"
+	"github.com/jwilner/grpcbreaker/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 func TestNew(t *testing.T) {
-	dir, err := os.MkdirTemp("", "")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		_ = os.RemoveAll(dir)
-	}()
-
-	l, err := net.Listen("unix", path.Join(dir, "foo.sock"))
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer func() {
-		_ = l.Close()
-	}()
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Between(0, 1, codes.Internal))
 
-	srvr := grpc.NewServer()
-	pbtest.RegisterSvcAServer(srvr, new(testServer))
-	go srvr.Serve(l)
-
-	ctx, cncl := context.WithCancel(context.Background())
-	defer cncl()
-
-	br := grpcbreaker.New(
-		ctx,
+	h := grpcbreakertest.NewBreaker(
+		t,
 		grpcbreaker.Global(
 			grpcbreaker.Predicate(func(err error) bool { return err != nil }),
 			grpcbreaker.FailThreshold(1),
@@ -49,63 +32,326 @@ func TestNew(t *testing.T) {
 		),
 	)
 
-	conn, err := grpc.Dial(
-		"unix://"+l.Addr().String(),
-		grpc.WithInsecure(),
-		grpc.WithUnaryInterceptor(br.UnaryInterceptor),
-	)
-	if err != nil {
-		t.Fatal(err)
-	}
+	client := pbtest.NewSvcAClient(backend.Dial(t, grpc.WithUnaryInterceptor(h.Breaker.UnaryInterceptor)))
+	ctx := context.Background()
+	global := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
 
-	client := pbtest.NewSvcAClient(conn)
-
-	_, err = client.Get(ctx, &pbtest.GetRequest{})
+	_, err := client.Get(ctx, &pbtest.GetRequest{})
 	if c := status.Code(err); c != codes.Internal {
 		t.Fatalf("Expected %v but got %v", codes.Internal, c)
 	}
-	assertSequence(t, br.Events, grpcbreaker.Closed, grpcbreaker.Open)
+	h.ExpectTransitions(global, grpcbreaker.Closed, grpcbreaker.Open)
+
 	if _, err = client.Get(ctx, &pbtest.GetRequest{}); !errors.Is(err, grpcbreaker.ErrBreakerOpen) {
 		t.Fatalf("Expected %v but got %v", grpcbreaker.ErrBreakerOpen, err)
 	}
+	h.ExpectShed(global)
+
+	h.Clock.Advance(100 * time.Second)
+	h.ExpectTransitions(global, grpcbreaker.Open, grpcbreaker.HalfOpen)
+
+	if _, err = client.Get(ctx, &pbtest.GetRequest{}); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	h.ExpectTransitions(global, grpcbreaker.HalfOpen, grpcbreaker.Closed)
+
+	if c := backend.Calls(); c != 2 {
+		t.Fatalf("Expected the backend to have seen 2 calls but got %d", c)
+	}
 }
 
-func assertSequence(
-	t *testing.T,
-	events <-chan grpcbreaker.Event,
-	start grpcbreaker.State,
-	states ...grpcbreaker.State,
-) {
-	t.Helper()
+func TestRecorder(t *testing.T) {
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Between(0, 1, codes.Internal))
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(grpcbreaker.FailThreshold(1)))
+
+	var buf bytes.Buffer
+	rec := h.Breaker.Recorder(&buf)
+
+	client := pbtest.NewSvcAClient(backend.Dial(
+		t,
+		grpc.WithChainUnaryInterceptor(rec.UnaryInterceptor, h.Breaker.UnaryInterceptor),
+	))
+
+	ctx := context.Background()
+	_, _ = client.Get(ctx, &pbtest.GetRequest{}, grpcbreaker.CallSite("checkout"))
+	h.Clock.Advance(time.Second)
+	_, _ = client.Get(ctx, &pbtest.GetRequest{}, grpcbreaker.CallSite("checkout"))
+
+	if err := rec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	callSite := &trace.Breaker{Type: "BreakerCallSite", Name: "checkout"}
+	expected := []trace.Record{
+		{
+			Version:  trace.Version,
+			Time:     time.Unix(0, 0),
+			Method:   "/pbtest.SvcA/Get",
+			CallSite: "checkout",
+			Code:     codes.Internal,
+			Breaker:  callSite,
+			State:    "Closed",
+		},
+		{
+			Version:  trace.Version,
+			Time:     time.Unix(1, 0),
+			Method:   "/pbtest.SvcA/Get",
+			CallSite: "checkout",
+			Code:     codes.Unknown,
+			Breaker:  callSite,
+			State:    "Open",
+			Shed:     true,
+		},
+	}
+
+	r := trace.NewReader(&buf)
+	for _, e := range expected {
+		got, err := r.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got.Time = got.Time.In(e.Time.Location())
+		if !reflect.DeepEqual(got, e) {
+			t.Fatalf("expected %+v but got %+v", e, got)
+		}
+	}
+}
+
+func TestRecorder_partitions(t *testing.T) {
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Sequence())
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(grpcbreaker.KeyFunc(grpcbreaker.OutgoingMetadata("tenant"))))
 
-	timeout := time.After(100 * time.Microsecond)
+	var buf bytes.Buffer
+	rec := h.Breaker.Recorder(&buf)
 
-	current := start
-	for len(states) > 0 {
-		next := states[0]
-		select {
-		case ev := <-events:
-			e, ok := ev.(grpcbreaker.StateEvent)
-			if !ok || !e.Transition() {
-				continue
+	// without the breaker's interceptor, so that only the recorder sees the call
+	client := pbtest.NewSvcAClient(backend.Dial(t, grpc.WithChainUnaryInterceptor(rec.UnaryInterceptor)))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "tenant", "a")
+	if _, err := client.Get(ctx, &pbtest.GetRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := trace.NewReader(&buf).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (trace.Breaker{Type: "BreakerGlobal", Partition: "a"}); *got.Breaker != expected {
+		t.Fatalf("expected %+v but got %+v", expected, *got.Breaker)
+	}
+	if _, ok := h.Breaker.Get(grpcbreaker.Key{Partition: "a"}); ok {
+		t.Fatal("expected recording not to create the partition")
+	}
+}
+
+// lockedBuffer is a bytes.Buffer which may be written by the goroutines recording abandoned streams
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records waits for at least one record to be written, returning all those which have been
+func (b *lockedBuffer) records(t *testing.T) []trace.Record {
+	t.Helper()
+	deadline := time.Now().Add(grpcbreakertest.DefaultTimeout)
+	for ; time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		b.mu.Lock()
+		data := append([]byte(nil), b.buf.Bytes()...)
+		b.mu.Unlock()
+		if len(data) == 0 {
+			continue
+		}
+
+		var recs []trace.Record
+		r := trace.NewReader(bytes.NewReader(data))
+		for {
+			rec, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				return recs
 			}
-			if e.Old.State() != current || e.New.State() != next {
-				t.Fatalf("Expected %v->%v but got %v->%v", current, next, e.Old.State(), e.New.State())
+			if err != nil {
+				t.Fatal(err)
 			}
-			current = next
-			states = states[1:]
-		case <-timeout:
-			t.Fatalf("Timed out waiting for state event")
+			recs = append(recs, rec)
 		}
 	}
+	t.Fatal("timed out waiting for a record")
+	return nil
 }
 
-type testServer struct {
-	pbtest.UnimplementedSvcAServer
+func TestRecorder_streams(t *testing.T) {
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Sequence())
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global())
+
+	newStream := func(t *testing.T, ctx context.Context, desc *grpc.StreamDesc) (grpc.ClientStream, *lockedBuffer) {
+		var buf lockedBuffer
+		rec := h.Breaker.Recorder(&buf)
+		conn := backend.Dial(t, grpc.WithChainStreamInterceptor(rec.StreamInterceptor))
+		cs, err := conn.NewStream(ctx, desc, "/pbtest.SvcA/Upload")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.SendMsg(&pbtest.GetRequest{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.CloseSend(); err != nil {
+			t.Fatal(err)
+		}
+		return cs, &buf
+	}
+
+	t.Run("client streaming", func(t *testing.T) {
+		cs, buf := newStream(t, context.Background(), &grpc.StreamDesc{ClientStreams: true})
+		if err := cs.RecvMsg(&pbtest.GetResponse{}); err != nil { // as CloseAndRecv does
+			t.Fatal(err)
+		}
+
+		recs := buf.records(t)
+		if len(recs) != 1 || recs[0].Method != "/pbtest.SvcA/Upload" || recs[0].Code != codes.OK {
+			t.Fatalf("expected one successful record but got %+v", recs)
+		}
+	})
+
+	t.Run("abandoned server stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cs, buf := newStream(t, ctx, &grpc.StreamDesc{ServerStreams: true})
+		if err := cs.RecvMsg(&pbtest.GetResponse{}); err != nil {
+			t.Fatal(err)
+		}
+		cancel() // without reading to the end
+
+		recs := buf.records(t)
+		if len(recs) != 1 || recs[0].Code != codes.Canceled {
+			t.Fatalf("expected one canceled record but got %+v", recs)
+		}
+	})
 }
 
-func (t *testServer) Get(context.Context, *pbtest.GetRequest) (*pbtest.GetResponse, error) {
-	return nil, status.Error(codes.Internal, "uh huh")
+func TestGeneratedPolicy(t *testing.T) {
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Sequence(
+		status.Error(codes.DeadlineExceeded, "slow"),
+		status.Error(codes.Internal, "bug"), // not a failure according to the policy of Get
+		status.Error(codes.Unavailable, "down"),
+	))
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(), pbtest.SvcABreakerPolicy()...)
+
+	client := pbtest.NewSvcAClient(backend.Dial(t, grpc.WithUnaryInterceptor(h.Breaker.UnaryInterceptor)))
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, _ = client.Get(ctx, &pbtest.GetRequest{})
+	}
+
+	// Get's breaker inherits the threshold and timeout declared on SvcA
+	get := grpcbreaker.Key{Type: grpcbreaker.BreakerMethod, Name: "/pbtest.SvcA/Get"}
+	h.ExpectTransitions(get, grpcbreaker.Closed, grpcbreaker.Open)
+	h.Clock.Advance(10 * time.Second)
+	h.ExpectTransitions(get, grpcbreaker.Open, grpcbreaker.HalfOpen)
+
+	if !pbtest.SvcAIdempotent["/pbtest.SvcA/Get"] || pbtest.SvcAFallbackable["/pbtest.SvcA/Get"] {
+		t.Fatalf("unexpected traits of Get")
+	}
 }
 
-var _ pbtest.SvcAServer = (*testServer)(nil)
+func TestBreaker_History(t *testing.T) {
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(grpcbreaker.ResetTimeout(time.Minute), grpcbreaker.HistorySize(2)))
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Fail(codes.OK))
+	client := pbtest.NewSvcAClient(backend.Dial(t, grpc.WithUnaryInterceptor(h.Breaker.UnaryInterceptor)))
+	global := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+	ctx := context.Background()
+
+	if err := h.Breaker.Override(global, grpcbreaker.Open); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ { // the first is sampled, then one a second
+		_, _ = client.Get(ctx, &pbtest.GetRequest{})
+		h.Clock.Advance(500 * time.Millisecond)
+	}
+
+	hist, ok := h.Breaker.History(global)
+	if !ok {
+		t.Fatal("expected history")
+	}
+	if hist.ShedTotal != 5 || len(hist.Transitions) != 1 || hist.Transitions[0].New.State() != grpcbreaker.Open {
+		t.Fatalf("unexpected history %+v", hist)
+	}
+
+	// only the last two samples are retained
+	var got []time.Time
+	for _, s := range hist.Shed {
+		if s.Skipped != 1 {
+			t.Fatalf("expected a shed request to be skipped between samples but got %+v", s)
+		}
+		got = append(got, s.Published)
+	}
+	if want := []time.Time{time.Unix(1, 0), time.Unix(2, 0)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected samples at %v but got %v", want, got)
+	}
+
+	if _, ok := h.Breaker.History(grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/pbtest.SvcA"}); ok {
+		t.Fatal("expected no history for a breaker which doesn't exist")
+	}
+}
+
+func TestCircuit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := grpcbreakertest.NewFakeClock(time.Unix(0, 0))
+	c := grpcbreaker.NewCircuit(
+		ctx,
+		"kafka",
+		grpcbreaker.FailThreshold(2),
+		grpcbreaker.ResetTimeout(time.Second),
+		grpcbreaker.WithClock(clk),
+	)
+
+	errDown := errors.New("broker unavailable")
+	calls := 0
+	failing := func(context.Context) error {
+		calls++
+		return errDown
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.Execute(ctx, failing); !errors.Is(err, errDown) {
+			t.Fatalf("wanted %v but got %v", errDown, err)
+		}
+	}
+	if err := c.Execute(ctx, failing); !errors.Is(err, grpcbreaker.ErrBreakerOpen) {
+		t.Fatalf("wanted %v but got %v", grpcbreaker.ErrBreakerOpen, err)
+	}
+	if calls != 2 {
+		t.Fatalf("wanted 2 calls but got %d", calls)
+	}
+
+	snaps := c.Snapshot()
+	want := grpcbreaker.Key{Type: grpcbreaker.BreakerCircuit, Name: "kafka"}
+	if len(snaps) != 1 || snaps[0].Key != want || snaps[0].State.State() != grpcbreaker.Open {
+		t.Fatalf("unexpected snapshot: %+v", snaps)
+	}
+
+	clk.Advance(time.Second)
+	if err := c.Execute(ctx, func(context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.Snapshot()[0].State.State(); s != grpcbreaker.Closed {
+		t.Fatalf("wanted %v but got %v", grpcbreaker.Closed, s)
+	}
+
+	hist, _ := c.History()
+	var states []grpcbreaker.State
+	for _, ev := range hist.Transitions {
+		states = append(states, ev.New.State())
+	}
+	if exp := []grpcbreaker.State{grpcbreaker.Open, grpcbreaker.HalfOpen, grpcbreaker.Closed}; !reflect.DeepEqual(states, exp) {
+		t.Fatalf("wanted transitions %v but got %v", exp, states)
+	}
+	if causes := hist.Transitions[0].Causes; len(causes) != 2 || causes[1].Message != errDown.Error() {
+		t.Fatalf("unexpected causes: %+v", causes)
+	}
+}
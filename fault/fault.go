@@ -0,0 +1,218 @@
+// Package fault provides a client interceptor which injects failures, latency and hangs into gRPC calls, so that
+// breaker policies can be seen to trip and recover as expected. Faults are configured for Methods and Services with
+// the same naming scheme as grpcbreaker's option sets, with the most specific match applying to each call.
+//
+// Chain the injector after grpcbreaker's interceptor, so that the breaker sees the injected faults:
+//
+//	grpc.WithChainUnaryInterceptor(breaker.UnaryInterceptor, injector.UnaryInterceptor)
+package fault
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Injector injects faults into the calls passing through its interceptors
+type Injector struct {
+	UnaryInterceptor  grpc.UnaryClientInterceptor
+	StreamInterceptor grpc.StreamClientInterceptor
+
+	global   *Rule
+	services map[string]*Rule
+	methods  map[string]*Rule
+}
+
+// Rule describes the faults to inject into the calls it matches
+type Rule struct {
+	kind ruleKind
+	name string
+	settings
+
+	calls int64 // atomic
+}
+
+type ruleKind int
+
+const (
+	ruleGlobal ruleKind = iota
+	ruleService
+	ruleMethod
+)
+
+type settings struct {
+	code        codes.Code
+	latency     time.Duration
+	hang        bool
+	probability float64
+	schedule    func(n int) bool
+}
+
+type Option func(*settings)
+
+// Code makes matching calls fail with the given code without reaching the server
+func Code(code codes.Code) Option {
+	return func(s *settings) {
+		s.code = code
+	}
+}
+
+// Latency delays matching calls by the given duration, after which they fail if a Code is set or otherwise proceed
+func Latency(d time.Duration) Option {
+	return func(s *settings) {
+		s.latency = d
+	}
+}
+
+// Hang makes matching calls block until their context is done. As grpcbreaker ignores failures caused by the caller's
+// own context, use Latency with Code(codes.DeadlineExceeded) to simulate timeouts which count against the breaker.
+func Hang() Option {
+	return func(s *settings) {
+		s.hang = true
+	}
+}
+
+// Probability sets the chance, from 0 to 1, that a fault is injected into a matching call; the default is 1
+func Probability(p float64) Option {
+	return func(s *settings) {
+		s.probability = p
+	}
+}
+
+// Schedule sets a function deciding whether a fault is injected into the nth call (counting from zero) matched by the
+// rule; it's combined with any Probability
+func Schedule(schedule func(n int) bool) Option {
+	return func(s *settings) {
+		s.schedule = schedule
+	}
+}
+
+// Global returns a Rule matching calls matched by no more specific rule
+func Global(opts ...Option) *Rule {
+	return newRule(ruleGlobal, "", opts)
+}
+
+// Service returns a Rule matching calls to any method of the service, unless matched by a Method rule
+func Service(name string, opts ...Option) *Rule {
+	return newRule(ruleService, name, opts)
+}
+
+// Method returns a Rule matching calls to the method
+func Method(name string, opts ...Option) *Rule {
+	return newRule(ruleMethod, name, opts)
+}
+
+func newRule(kind ruleKind, name string, opts []Option) *Rule {
+	r := &Rule{kind: kind, name: name, settings: settings{probability: 1}}
+	for _, o := range opts {
+		o(&r.settings)
+	}
+	return r
+}
+
+// New returns an Injector applying the given rules; later rules replace earlier ones for the same name
+func New(rules ...*Rule) *Injector {
+	inj := &Injector{services: make(map[string]*Rule), methods: make(map[string]*Rule)}
+	for _, r := range rules {
+		switch r.kind {
+		case ruleGlobal:
+			inj.global = r
+		case ruleService:
+			inj.services[r.name] = r
+		case ruleMethod:
+			inj.methods[r.name] = r
+		}
+	}
+
+	inj.UnaryInterceptor = func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if err := inj.inject(ctx, method); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	inj.StreamInterceptor = func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if err := inj.inject(ctx, method); err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	return inj
+}
+
+// match returns the most specific rule for the method, or nil
+func (inj *Injector) match(method string) *Rule {
+	if r, ok := inj.methods[method]; ok {
+		return r
+	}
+	if idx := strings.Index(method[1:], "/") + 1; idx > 0 {
+		if r, ok := inj.services[method[:idx]]; ok {
+			return r
+		}
+	}
+	return inj.global
+}
+
+// inject applies the faults of the matching rule, if any, returning the error with which the call should fail
+func (inj *Injector) inject(ctx context.Context, method string) error {
+	r := inj.match(method)
+	if r == nil {
+		return nil
+	}
+
+	n := int(atomic.AddInt64(&r.calls, 1) - 1)
+	if r.schedule != nil && !r.schedule(n) {
+		return nil
+	}
+	if r.probability < 1 && rand.Float64() >= r.probability {
+		return nil
+	}
+
+	if r.hang {
+		<-ctx.Done()
+		return contextError(ctx)
+	}
+
+	if r.latency > 0 {
+		t := time.NewTimer(r.latency)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return contextError(ctx)
+		}
+	}
+
+	if r.code != codes.OK {
+		return status.Errorf(r.code, "fault injected into %v", method)
+	}
+	return nil
+}
+
+func contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+	}
+	return status.Error(codes.Canceled, ctx.Err().Error())
+}
@@ -0,0 +1,75 @@
+package fault_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/fault"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestInjector(t *testing.T) {
+	backend := grpcbreakertest.NewBackend(t, nil)
+	h := grpcbreakertest.NewBreaker(
+		t,
+		grpcbreaker.Global(grpcbreaker.FailThreshold(2), grpcbreaker.ResetTimeout(10*time.Second)),
+		grpcbreaker.Method("/pkg.Svc/Get"),
+	)
+	inj := fault.New(
+		fault.Global(fault.Hang()),
+		fault.Service("/pkg.Svc", fault.Latency(time.Millisecond)),
+		fault.Method("/pkg.Svc/Get", fault.Code(codes.Unavailable), fault.Schedule(func(n int) bool { return n < 2 })),
+	)
+	conn := backend.Dial(t, grpc.WithChainUnaryInterceptor(h.Breaker.UnaryInterceptor, inj.UnaryInterceptor))
+
+	invoke := func(ctx context.Context, method string) error {
+		return conn.Invoke(ctx, method, new(emptypb.Empty), new(emptypb.Empty))
+	}
+	ctx := context.Background()
+	get := grpcbreaker.Key{Type: grpcbreaker.BreakerMethod, Name: "/pkg.Svc/Get"}
+
+	// the first two calls are scheduled to fail, tripping the breaker
+	for i := 0; i < 2; i++ {
+		if err := invoke(ctx, "/pkg.Svc/Get"); status.Code(err) != codes.Unavailable {
+			t.Fatalf("expected %v but got %v", codes.Unavailable, err)
+		}
+	}
+	h.ExpectTransitions(get, grpcbreaker.Closed, grpcbreaker.Open)
+	if err := invoke(ctx, "/pkg.Svc/Get"); !errors.Is(err, grpcbreaker.ErrBreakerOpen) {
+		t.Fatalf("expected %v but got %v", grpcbreaker.ErrBreakerOpen, err)
+	}
+
+	// subsequent calls aren't, so the breaker recovers
+	h.Clock.Advance(10 * time.Second)
+	if err := invoke(ctx, "/pkg.Svc/Get"); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	h.ExpectTransitions(get, grpcbreaker.Open, grpcbreaker.HalfOpen, grpcbreaker.Closed)
+
+	// other methods of the service are only delayed
+	start := time.Now()
+	if err := invoke(ctx, "/pkg.Svc/List"); err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Millisecond {
+		t.Fatalf("expected at least %v of latency but got %v", time.Millisecond, elapsed)
+	}
+
+	// and everything else hangs
+	tctx, cncl := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cncl()
+	if err := invoke(tctx, "/other.Svc/Get"); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected %v but got %v", codes.DeadlineExceeded, err)
+	}
+
+	if c := backend.Calls(); c != 2 {
+		t.Fatalf("expected only 2 calls to reach the backend but got %d", c)
+	}
+}
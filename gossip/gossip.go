@@ -0,0 +1,406 @@
+// Package gossip shares breaker state between client processes without a central store. Each Node periodically pushes
+// everything it knows -- the members it's heard of and the latest state of every peer's breakers -- to a few random
+// members over UDP, and merges what it receives by peer, Key, incarnation and generation.
+//
+// A Node is a grpcbreaker.SharedState, so it's used with grpcbreaker.Share just like a central store would be, with the
+// node's name as the peer.
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+const (
+	// maxMessageSize is the most a message is encoded to before its members and states are split across several,
+	// chosen to fit within the MTU of most paths so that datagrams aren't fragmented
+	maxMessageSize = 1200
+	// maxDatagramSize is the largest UDP payload; a single member or state larger than maxMessageSize is still sent
+	// alone in a message up to this size
+	maxDatagramSize = 65507
+)
+
+var errTooLarge = errors.New("message too large for a datagram")
+
+// Node is a member of a gossip cluster
+type Node struct {
+	name string
+	conn *net.UDPConn
+
+	seeds                         []string
+	interval, deadAfter, stateTTL time.Duration
+	fanout                        int
+	incarnation                   int64 // distinguishes this run of the process from earlier ones
+
+	mu      sync.Mutex
+	members map[string]member // by name, excluding this node
+	states  map[entryKey]entry
+	stats   Stats
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+type member struct {
+	Addr  string
+	Heard time.Time // when last heard from directly by anybody, so the dead aren't resurrected by hearsay
+}
+
+type entryKey struct {
+	peer string
+	key  grpcbreaker.Key
+}
+
+// entry is a peer's state as gossiped. Its incarnation is that of the node to which the state was published, so that
+// after a restart, when its generations begin again from zero, a peer's states aren't outranked by those from before.
+type entry struct {
+	grpcbreaker.PeerState
+	Incarnation int64
+}
+
+// message is the JSON payload of every datagram
+type message struct {
+	From    string
+	Addr    string
+	Members map[string]member // by name
+	States  []entry
+}
+
+// Stats describe the traffic a Node has seen and how quickly states are converging on it
+type Stats struct {
+	Sent, Received int // messages
+	SendErrors     int // messages which couldn't be sent, e.g. as they were too large
+	Updates        int // states received which were newer than those already known
+	// MeanConvergence and MaxConvergence are the delay between a state being reported by its peer and its arriving
+	// at this node, over all Updates
+	MeanConvergence, MaxConvergence time.Duration
+
+	totalConvergence time.Duration
+}
+
+// Option configures a Node
+type Option func(*Node)
+
+// Seeds sets the addresses of nodes through which to join the cluster
+func Seeds(addrs ...string) Option {
+	return func(n *Node) {
+		n.seeds = addrs
+	}
+}
+
+// Interval sets how often the node gossips; the default is 200ms
+func Interval(d time.Duration) Option {
+	return func(n *Node) {
+		n.interval = d
+	}
+}
+
+// Fanout sets how many members the node gossips to each interval; the default is 3
+func Fanout(fanout int) Option {
+	return func(n *Node) {
+		n.fanout = fanout
+	}
+}
+
+// DeadAfter sets how long a member may go unheard before it's forgotten; the default is 10 intervals
+func DeadAfter(d time.Duration) Option {
+	return func(n *Node) {
+		n.deadAfter = d
+	}
+}
+
+// StateTTL sets how long a peer's state is kept without a newer report, so that departed peers are forgotten; the
+// default is a minute, which should comfortably exceed the interval at which states are shared
+func StateTTL(d time.Duration) Option {
+	return func(n *Node) {
+		n.stateTTL = d
+	}
+}
+
+// Start listens on the UDP address as a node with the given name, which must be unique within the cluster, and
+// starts gossiping
+func Start(name, addr string, opts ...Option) (*Node, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Node{
+		name:        name,
+		conn:        conn,
+		interval:    200 * time.Millisecond,
+		stateTTL:    time.Minute,
+		fanout:      3,
+		incarnation: time.Now().UnixNano(),
+		members:     make(map[string]member),
+		states:      make(map[entryKey]entry),
+		done:        make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(n)
+	}
+	if n.deadAfter == 0 {
+		n.deadAfter = 10 * n.interval
+	}
+
+	n.wg.Add(2)
+	go n.receive()
+	go n.gossip()
+	return n, nil
+}
+
+// Addr returns the address the node is listening on
+func (n *Node) Addr() string {
+	return n.conn.LocalAddr().String()
+}
+
+// Members returns the names of the other members the node currently knows of
+func (n *Node) Members() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	names := make([]string, 0, len(n.members))
+	for name := range n.members {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Stats returns the node's statistics so far
+func (n *Node) Stats() Stats {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.stats
+}
+
+// Publish records the state of one of this process's breakers, to be gossiped from the next interval
+func (n *Node) Publish(_ context.Context, state grpcbreaker.PeerState) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.states[entryKey{state.Peer, state.Key}] = entry{state, n.incarnation}
+	return nil
+}
+
+// Peers returns the latest state known for the breaker from each peer
+func (n *Node) Peers(_ context.Context, key grpcbreaker.Key) ([]grpcbreaker.PeerState, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var states []grpcbreaker.PeerState
+	for k, e := range n.states {
+		if k.key == key {
+			states = append(states, e.PeerState)
+		}
+	}
+	return states, nil
+}
+
+// Close stops gossiping and closes the listener
+func (n *Node) Close() (err error) {
+	n.closeOnce.Do(func() {
+		close(n.done)
+		err = n.conn.Close()
+		n.wg.Wait()
+	})
+	return err
+}
+
+func (n *Node) gossip() {
+	defer n.wg.Done()
+
+	t := time.NewTicker(n.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			n.gossipOnce()
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// gossipOnce sends everything the node knows to up to fanout random members, or to the seeds if it knows of none
+func (n *Node) gossipOnce() {
+	n.mu.Lock()
+
+	now := time.Now()
+	msg := message{From: n.name, Addr: n.Addr(), Members: make(map[string]member, len(n.members))}
+	targets := make([]string, 0, len(n.members))
+	for name, m := range n.members {
+		if now.Sub(m.Heard) > n.deadAfter {
+			delete(n.members, name)
+			continue
+		}
+		msg.Members[name] = m
+		targets = append(targets, m.Addr)
+	}
+	for k, e := range n.states {
+		if now.Sub(e.Reported) > n.stateTTL {
+			delete(n.states, k)
+			continue
+		}
+		msg.States = append(msg.States, e)
+	}
+
+	n.mu.Unlock()
+
+	if len(targets) == 0 {
+		targets = append(targets, n.seeds...)
+	}
+	rand.Shuffle(len(targets), func(i, j int) { targets[i], targets[j] = targets[j], targets[i] })
+	if len(targets) > n.fanout {
+		targets = targets[:n.fanout]
+	}
+
+	for _, target := range targets {
+		addr, err := net.ResolveUDPAddr("udp", target)
+		if err != nil {
+			continue
+		}
+		n.send(addr, msg)
+	}
+}
+
+// send writes the message to addr, splitting its members and states across as many datagrams as needed. A message
+// which can't be sent is counted in Stats and the rest are sent regardless.
+func (n *Node) send(addr *net.UDPAddr, msg message) {
+	for _, chunk := range split(msg) {
+		b, err := json.Marshal(chunk)
+		if err == nil && len(b) > maxDatagramSize {
+			err = errTooLarge
+		}
+		if err == nil {
+			_, err = n.conn.WriteToUDP(b, addr)
+		}
+
+		n.mu.Lock()
+		if err != nil {
+			n.stats.SendErrors++
+		} else {
+			n.stats.Sent++
+		}
+		n.mu.Unlock()
+	}
+}
+
+// split divides the message's members and states into messages each encoding to no more than maxMessageSize, except
+// those holding a single larger member or state. There's always at least one, so that an empty message still
+// announces its sender.
+func split(msg message) []message {
+	empty := func() message {
+		return message{From: msg.From, Addr: msg.Addr, Members: map[string]member{}, States: []entry{}}
+	}
+	b, _ := json.Marshal(empty())
+	base := len(b)
+
+	var (
+		chunks []message
+		cur    = empty()
+		size   = base
+	)
+	// add accounts for an item encoding to n bytes, plus a separator, starting another message if it won't fit
+	add := func(n int) {
+		if size+n+1 > maxMessageSize && (len(cur.Members) > 0 || len(cur.States) > 0) {
+			chunks = append(chunks, cur)
+			cur, size = empty(), base
+		}
+		size += n + 1
+	}
+
+	for name, m := range msg.Members {
+		k, _ := json.Marshal(name)
+		v, _ := json.Marshal(m)
+		add(len(k) + 1 + len(v))
+		cur.Members[name] = m
+	}
+	for _, st := range msg.States {
+		v, _ := json.Marshal(st)
+		add(len(v))
+		cur.States = append(cur.States, st)
+	}
+	return append(chunks, cur)
+}
+
+func (n *Node) receive() {
+	defer n.wg.Done()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		size, _, err := n.conn.ReadFromUDP(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+
+		var msg message
+		if json.Unmarshal(buf[:size], &msg) != nil || msg.From == "" {
+			continue // not gossip
+		}
+		n.merge(msg, time.Now())
+	}
+}
+
+// merge learns the sender and its members, and keeps whichever state of each peer's breaker is the newer
+func (n *Node) merge(msg message, now time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.stats.Received++
+
+	n.members[msg.From] = member{msg.Addr, now}
+	for name, m := range msg.Members {
+		if cur, ok := n.members[name]; name != n.name && now.Sub(m.Heard) <= n.deadAfter && (!ok || m.Heard.After(cur.Heard)) {
+			n.members[name] = m
+		}
+	}
+
+	for _, st := range msg.States {
+		if st.Peer == n.name || now.Sub(st.Reported) > n.stateTTL {
+			continue // nobody knows better than us about our own breakers, and expired states stay forgotten
+		}
+		k := entryKey{st.Peer, st.Key}
+		if cur, ok := n.states[k]; ok && !newer(st, cur) {
+			continue
+		}
+		n.states[k] = st
+
+		n.stats.Updates++
+		delay := now.Sub(st.Reported)
+		n.stats.totalConvergence += delay
+		n.stats.MeanConvergence = n.stats.totalConvergence / time.Duration(n.stats.Updates)
+		if delay > n.stats.MaxConvergence {
+			n.stats.MaxConvergence = delay
+		}
+	}
+}
+
+// newer reports whether a is a more recent state of a breaker than b: a later incarnation always wins, then a later
+// generation, with the report time breaking ties
+func newer(a, b entry) bool {
+	if a.Incarnation != b.Incarnation {
+		return a.Incarnation > b.Incarnation
+	}
+	if a.State.Gen() != b.State.Gen() {
+		return a.State.Gen() > b.State.Gen()
+	}
+	return a.Reported.After(b.Reported)
+}
+
+var _ grpcbreaker.SharedState = (*Node)(nil)
@@ -0,0 +1,263 @@
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+func startCluster(t *testing.T, size int) []*Node {
+	t.Helper()
+
+	var nodes []*Node
+	for i := 0; i < size; i++ {
+		var seeds []string
+		if i > 0 {
+			seeds = []string{nodes[0].Addr()}
+		}
+		n, err := Start(fmt.Sprint("node-", i), "127.0.0.1:0", Seeds(seeds...), Interval(5*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = n.Close() })
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// eventually polls cond until it's true or a few seconds have passed
+func eventually(t *testing.T, desc string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting until %v", desc)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNode(t *testing.T) {
+	nodes := startCluster(t, 5)
+	ctx := context.Background()
+	key := grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/foo.Svc"}
+
+	eventually(t, "all members are known", func() bool {
+		for _, n := range nodes {
+			if len(n.Members()) != len(nodes)-1 {
+				return false
+			}
+		}
+		return true
+	})
+
+	opened := grpcbreaker.PeerState{
+		Peer:     "node-3",
+		Key:      key,
+		State:    grpcbreaker.GenState(grpcbreaker.Closed).Next(grpcbreaker.Open),
+		Reported: time.Now(),
+	}
+	if err := nodes[3].Publish(ctx, opened); err != nil {
+		t.Fatal(err)
+	}
+
+	converged := func(want grpcbreaker.PeerState) func() bool {
+		return func() bool {
+			for _, n := range nodes {
+				peers, _ := n.Peers(ctx, key)
+				if len(peers) != 1 || peers[0].State != want.State {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	eventually(t, "the open state has spread", converged(opened))
+
+	for _, n := range nodes {
+		if s := n.Stats(); n != nodes[3] && (s.Updates == 0 || s.MaxConvergence <= 0 || s.MeanConvergence > s.MaxConvergence) {
+			t.Fatalf("%v: unexpected stats %+v", n.name, s)
+		}
+	}
+
+	t.Run("later generations win", func(t *testing.T) {
+		halfOpen := opened
+		halfOpen.State = opened.State.Next(grpcbreaker.HalfOpen)
+		halfOpen.Reported = opened.Reported.Add(-time.Second) // a skewed clock doesn't matter
+		if err := nodes[3].Publish(ctx, halfOpen); err != nil {
+			t.Fatal(err)
+		}
+		eventually(t, "the half open state has spread", converged(halfOpen))
+	})
+}
+
+func TestNode_forgetsDeadMembers(t *testing.T) {
+	nodes := startCluster(t, 3)
+
+	eventually(t, "all members are known", func() bool { return len(nodes[0].Members()) == 2 })
+	_ = nodes[2].Close()
+	eventually(t, "the closed node is forgotten", func() bool { return len(nodes[0].Members()) == 1 })
+}
+
+func TestNode_restartedPeer(t *testing.T) {
+	n := &Node{name: "a", stateTTL: time.Minute, members: make(map[string]member), states: make(map[entryKey]entry)}
+	key := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+	now := time.Now()
+
+	open := grpcbreaker.GenState(grpcbreaker.Closed).Next(grpcbreaker.Open).Next(grpcbreaker.HalfOpen).Next(grpcbreaker.Open)
+	before := grpcbreaker.PeerState{Peer: "b", Key: key, State: open, Reported: now.Add(-time.Second)}
+	n.merge(message{From: "b", States: []entry{{before, 1}}}, now)
+
+	// after restarting, b's generations begin again from zero
+	after := grpcbreaker.PeerState{Peer: "b", Key: key, State: grpcbreaker.GenState(grpcbreaker.Closed), Reported: now}
+	n.merge(message{From: "b", States: []entry{{after, 2}}}, now)
+
+	// and the report from before the restart, arriving late by way of another member, doesn't undo that
+	n.merge(message{From: "c", States: []entry{{before, 1}}}, now)
+
+	states, _ := n.Peers(context.Background(), key)
+	if len(states) != 1 || states[0].State != after.State {
+		t.Fatalf("expected the restarted peer's state %v but got %+v", after.State, states)
+	}
+}
+
+func TestNode_forgetsExpiredStates(t *testing.T) {
+	n, err := Start("a", "127.0.0.1:0", Interval(10*time.Millisecond), StateTTL(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = n.Close() })
+
+	key := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+	expired := grpcbreaker.PeerState{Peer: "a", Key: key, Reported: time.Now().Add(-2 * time.Second)}
+	if err := n.Publish(context.Background(), expired); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the expired state is forgotten", func() bool {
+		states, _ := n.Peers(context.Background(), key)
+		return len(states) == 0
+	})
+}
+
+// listen returns a node which gossips only when told to and a socket on which to receive what it sends
+func listen(t *testing.T) (*Node, *net.UDPConn) {
+	n, err := Start("a", "127.0.0.1:0", Interval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = n.Close() })
+
+	lis, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	return n, lis
+}
+
+// receive returns the messages arriving on lis until none has for a while, failing if any is too large
+func receive(t *testing.T, lis *net.UDPConn) []message {
+	t.Helper()
+
+	var msgs []message
+	buf := make([]byte, maxDatagramSize)
+	for {
+		_ = lis.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		size, _, err := lis.ReadFromUDP(buf)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return msgs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got message
+		if err := json.Unmarshal(buf[:size], &got); err != nil {
+			t.Fatal(err)
+		}
+		if size > maxMessageSize && len(got.Members)+len(got.States) > 1 {
+			t.Fatalf("expected at most %d bytes unless alone but got %d for %d members and %d states",
+				maxMessageSize, size, len(got.Members), len(got.States))
+		}
+		msgs = append(msgs, got)
+	}
+}
+
+func TestNode_send(t *testing.T) {
+	n, lis := listen(t)
+
+	msg := message{From: "a", Addr: n.Addr(), Members: make(map[string]member)}
+	for i := 0; i < 250; i++ {
+		msg.Members[fmt.Sprint("member-", i)] = member{Addr: "127.0.0.1:1", Heard: time.Now()}
+	}
+	for i := 0; i < 150; i++ {
+		msg.States = append(msg.States, entry{PeerState: grpcbreaker.PeerState{Peer: fmt.Sprint("peer-", i)}})
+	}
+	n.send(lis.LocalAddr().(*net.UDPAddr), msg)
+
+	members, states := make(map[string]bool), 0
+	msgs := receive(t, lis)
+	for _, got := range msgs {
+		for name := range got.Members {
+			members[name] = true
+		}
+		states += len(got.States)
+	}
+	if len(members) != 250 || states != 150 {
+		t.Fatalf("expected all 250 members and 150 states but got %d and %d", len(members), states)
+	}
+	if s := n.Stats(); s.Sent != len(msgs) || s.SendErrors != 0 {
+		t.Fatalf("expected %d messages sent without error but got %+v", len(msgs), s)
+	}
+}
+
+func TestNode_sendLongKeys(t *testing.T) {
+	n, lis := listen(t)
+
+	long := func(i, size int) entry {
+		name := fmt.Sprintf("/pkg.Svc/%v%v", i, strings.Repeat("x", size))
+		key := grpcbreaker.Key{Type: grpcbreaker.BreakerMethod, Name: name}
+		return entry{PeerState: grpcbreaker.PeerState{Peer: "b", Key: key}}
+	}
+	msg := message{From: "a", Addr: n.Addr()}
+	for i := 0; i < 20; i++ {
+		msg.States = append(msg.States, long(i, 500))
+	}
+	msg.States = append(msg.States, long(20, 2000))            // too large to share a message
+	msg.States = append(msg.States, long(21, maxDatagramSize)) // too large to send at all
+	n.send(lis.LocalAddr().(*net.UDPAddr), msg)
+
+	states := 0
+	for _, got := range receive(t, lis) {
+		states += len(got.States)
+	}
+	if states != 21 {
+		t.Fatalf("expected all but the largest of 22 states but got %d", states)
+	}
+	if s := n.Stats(); s.SendErrors != 1 {
+		t.Fatalf("expected 1 send error but got %d", s.SendErrors)
+	}
+}
+
+func TestNode_sendErrors(t *testing.T) {
+	n, _ := listen(t)
+
+	msg := message{From: "a", Addr: n.Addr(), Members: make(map[string]member)}
+	for i := 0; i < 100; i++ {
+		msg.Members[fmt.Sprint("member-", i)] = member{Addr: "127.0.0.1:1", Heard: time.Now()}
+	}
+	n.send(&net.UDPAddr{IP: net.IPv6loopback, Port: 1}, msg) // unreachable from an IPv4 socket
+
+	if s := n.Stats(); s.Sent != 0 || s.SendErrors < 2 {
+		t.Fatalf("expected every one of several messages to fail but got %+v", s)
+	}
+}
@@ -0,0 +1,123 @@
+package grpcbreakertest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Schedule decides the result of the nth call (counting from zero) to a Backend: nil for success or else an error
+type Schedule func(n int, method string) error
+
+// Fail returns a Schedule under which every call fails with the given code
+func Fail(code codes.Code) Schedule {
+	return func(int, string) error {
+		return status.Error(code, "scheduled failure")
+	}
+}
+
+// Sequence returns a Schedule under which the nth call gets the nth error, and all calls beyond them succeed
+func Sequence(errs ...error) Schedule {
+	return func(n int, _ string) error {
+		if n < len(errs) {
+			return errs[n]
+		}
+		return nil
+	}
+}
+
+// Between returns a Schedule under which calls from the nth up to but not including the mth fail with the given code
+func Between(n, m int, code codes.Code) Schedule {
+	return func(i int, _ string) error {
+		if n <= i && i < m {
+			return status.Error(code, "scheduled failure")
+		}
+		return nil
+	}
+}
+
+// Backend is an in-process gRPC server which answers every method of every service according to a Schedule.
+// Successful calls get an empty response, which decodes as the zero value of any message type.
+type Backend struct {
+	lis *bufconn.Listener
+
+	mu       sync.Mutex
+	schedule Schedule
+	calls    int
+}
+
+// NewBackend starts a Backend which runs until the test finishes; a nil schedule means every call succeeds
+func NewBackend(t testing.TB, schedule Schedule) *Backend {
+	b := &Backend{lis: bufconn.Listen(1 << 20), schedule: schedule}
+
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(b.handle))
+	go func() {
+		_ = srv.Serve(b.lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return b
+}
+
+// Dial connects to the backend; the connection is closed when the test finishes
+func (b *Backend) Dial(t testing.TB, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+
+	conn, err := grpc.Dial(
+		"bufconn",
+		append(
+			[]grpc.DialOption{
+				grpc.WithInsecure(),
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return b.lis.Dial() }),
+			},
+			opts...,
+		)...,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// SetSchedule replaces the backend's schedule; the call count carries on from where it was
+func (b *Backend) SetSchedule(schedule Schedule) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.schedule = schedule
+}
+
+// Calls returns the number of calls the backend has received
+func (b *Backend) Calls() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls
+}
+
+func (b *Backend) handle(_ interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+
+	if err := stream.RecvMsg(new(emptypb.Empty)); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	n, schedule := b.calls, b.schedule
+	b.calls++
+	b.mu.Unlock()
+
+	if schedule != nil {
+		if err := schedule(n, method); err != nil {
+			return err
+		}
+	}
+	return stream.SendMsg(new(emptypb.Empty))
+}
@@ -0,0 +1,52 @@
+package grpcbreakertest
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestBackend(t *testing.T) {
+	b := NewBackend(t, Between(1, 3, codes.Unavailable))
+	conn := b.Dial(t)
+
+	invoke := func() codes.Code {
+		return status.Code(conn.Invoke(context.Background(), "/pbtest.SvcA/Get", new(emptypb.Empty), new(emptypb.Empty)))
+	}
+
+	var got []codes.Code
+	for i := 0; i < 4; i++ {
+		got = append(got, invoke())
+	}
+	exp := []codes.Code{codes.OK, codes.Unavailable, codes.Unavailable, codes.OK}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Fatalf("expected %v but got %v", exp, got)
+		}
+	}
+
+	// the count carries on under a new schedule
+	b.SetSchedule(func(n int, method string) error {
+		if n != 4 || method != "/pbtest.SvcA/Get" {
+			t.Errorf("unexpected call %d to %v", n, method)
+		}
+		return status.Error(codes.Internal, "scheduled failure")
+	})
+	if c := invoke(); c != codes.Internal {
+		t.Fatalf("expected %v but got %v", codes.Internal, c)
+	}
+	if n := b.Calls(); n != 5 {
+		t.Fatalf("expected 5 calls but got %d", n)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	errUnavailable := status.Error(codes.Unavailable, "down")
+	s := Sequence(nil, errUnavailable)
+	if s(0, "") != nil || s(1, "") != errUnavailable || s(2, "") != nil {
+		t.Fatal("expected the nth call to get the nth error, and later calls none")
+	}
+}
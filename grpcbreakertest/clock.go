@@ -0,0 +1,18 @@
+// Package grpcbreakertest provides utilities for testing code which uses grpcbreaker
+package grpcbreakertest
+
+import (
+	"time"
+
+	"github.com/jwilner/grpcbreaker/internal/clock"
+)
+
+// FakeClock is a grpcbreaker.Clock which only moves when advanced, so that tests control exactly when timed
+// transitions (e.g. Open -> HalfOpen) happen. Timers fire synchronously within the call to Advance or Set which passes
+// their deadlines.
+type FakeClock = clock.Fake
+
+// NewFakeClock returns a FakeClock set to the given time; use it with grpcbreaker.WithClock
+func NewFakeClock(now time.Time) *FakeClock {
+	return clock.NewFake(now)
+}
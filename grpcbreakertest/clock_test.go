@@ -0,0 +1,130 @@
+package grpcbreakertest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFakeClock_sameInstant(t *testing.T) {
+	clk := NewFakeClock(time.Unix(0, 0))
+
+	var fired []string
+	record := func(name string) func() {
+		return func() { fired = append(fired, fmt.Sprintf("%v@%d", name, clk.Now().Unix())) }
+	}
+	clk.AfterFunc(time.Second, record("a"))
+	clk.AfterFunc(2*time.Second, record("c"))
+	clk.AfterFunc(time.Second, record("b"))
+	timer := clk.NewTimer(time.Second)
+
+	clk.Advance(5 * time.Second)
+
+	// timers due at the same instant fire in the order they were scheduled, and all see their own deadline
+	exp := []string{"a@1", "b@1", "c@2"}
+	if !reflect.DeepEqual(fired, exp) {
+		t.Fatalf("expected %v but got %v", exp, fired)
+	}
+	select {
+	case now := <-timer.C():
+		if !now.Equal(time.Unix(1, 0)) {
+			t.Fatalf("expected the timer to deliver %v but got %v", time.Unix(1, 0), now)
+		}
+	default:
+		t.Fatal("expected the timer to have fired")
+	}
+	if now := clk.Now(); !now.Equal(time.Unix(5, 0)) {
+		t.Fatalf("expected %v but got %v", time.Unix(5, 0), now)
+	}
+}
+
+func TestFakeClock_scheduledWhileFiring(t *testing.T) {
+	clk := NewFakeClock(time.Unix(0, 0))
+
+	fired := 0
+	clk.AfterFunc(time.Second, func() {
+		fired++
+		// due now, and so still within the Advance in progress
+		clk.AfterFunc(0, func() { fired++ })
+		// due after the Advance's target
+		clk.AfterFunc(time.Hour, func() { fired++ })
+	})
+
+	clk.Advance(time.Second)
+	if fired != 2 {
+		t.Fatalf("expected 2 timers to fire but got %d", fired)
+	}
+}
+
+func TestFakeClock_Stop(t *testing.T) {
+	clk := NewFakeClock(time.Unix(0, 0))
+
+	fired := false
+	timer := clk.AfterFunc(time.Second, func() { fired = true })
+	if !timer.Stop() {
+		t.Fatal("expected Stop to report an active timer")
+	}
+	if timer.Stop() {
+		t.Fatal("expected a second Stop to report an inactive timer")
+	}
+
+	clk.Advance(time.Second)
+	if fired {
+		t.Fatal("expected the stopped timer not to fire")
+	}
+
+	fires := clk.AfterFunc(time.Second, func() {})
+	clk.Advance(time.Second)
+	if fires.Stop() {
+		t.Fatal("expected Stop to report a timer which already fired as inactive")
+	}
+}
+
+func TestFakeClock_Reset(t *testing.T) {
+	clk := NewFakeClock(time.Unix(0, 0))
+
+	var fired []time.Time
+	timer := clk.AfterFunc(time.Second, func() { fired = append(fired, clk.Now()) })
+
+	// pushing the deadline back
+	if !timer.Reset(3 * time.Second) {
+		t.Fatal("expected Reset to report an active timer")
+	}
+	clk.Advance(2 * time.Second)
+	if len(fired) != 0 {
+		t.Fatalf("expected the timer not to fire before its new deadline but it fired at %v", fired)
+	}
+	clk.Advance(time.Second)
+
+	// rescheduling after firing, and after stopping
+	if timer.Reset(time.Second) {
+		t.Fatal("expected Reset to report a timer which already fired as inactive")
+	}
+	clk.Advance(time.Second)
+	timer.Stop()
+	if timer.Reset(time.Second) {
+		t.Fatal("expected Reset to report a stopped timer as inactive")
+	}
+	clk.Advance(time.Second)
+
+	if exp := []time.Time{time.Unix(3, 0), time.Unix(4, 0), time.Unix(5, 0)}; !reflect.DeepEqual(fired, exp) {
+		t.Fatalf("expected the timer to fire at %v but got %v", exp, fired)
+	}
+}
+
+func TestFakeClock_Set(t *testing.T) {
+	clk := NewFakeClock(time.Unix(10, 0))
+
+	fired := false
+	clk.AfterFunc(5*time.Second, func() { fired = true })
+
+	clk.Set(time.Unix(0, 0))
+	if now := clk.Now(); !now.Equal(time.Unix(10, 0)) {
+		t.Fatalf("expected the clock not to move backward but it's at %v", now)
+	}
+	clk.Set(time.Unix(15, 0))
+	if !fired {
+		t.Fatal("expected the timer to fire once its deadline was passed")
+	}
+}
@@ -0,0 +1,31 @@
+package grpcbreakertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+// Harness is a Breaker running on a FakeClock, with its events consumed by an EventRecorder
+type Harness struct {
+	*EventRecorder
+	Breaker *grpcbreaker.Breaker
+	Clock   *FakeClock
+}
+
+// NewBreaker builds a Breaker as grpcbreaker.New would but on a FakeClock set to the Unix epoch. The breaker is stopped
+// when the test finishes.
+func NewBreaker(t testing.TB, g *grpcbreaker.GlobalOptionSet, optionSets ...*grpcbreaker.OptionSet) *Harness {
+	ctx, cncl := context.WithCancel(context.Background())
+	t.Cleanup(cncl)
+
+	clk := NewFakeClock(time.Unix(0, 0))
+	br, err := grpcbreaker.New(ctx, g.With(grpcbreaker.WithClock(clk)), optionSets...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Harness{NewEventRecorder(t, br.Events), br, clk}
+}
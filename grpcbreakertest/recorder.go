@@ -0,0 +1,158 @@
+package grpcbreakertest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+// DefaultTimeout is how long an EventRecorder waits for an expected event by default
+const DefaultTimeout = time.Second
+
+// EventRecorder consumes a Breaker's events, retaining them so that tests can make assertions about them
+type EventRecorder struct {
+	// Timeout is how long Expect methods wait for events before failing the test
+	Timeout time.Duration
+
+	t testing.TB
+
+	mu      sync.Mutex
+	events  []grpcbreaker.Event
+	updated chan struct{}           // closed and replaced whenever an event is recorded
+	cursors map[grpcbreaker.Key]int // per key, the index after the last event matched by an Expect method
+}
+
+// NewEventRecorder starts consuming the events channel until it's closed or the test finishes
+func NewEventRecorder(t testing.TB, events <-chan grpcbreaker.Event) *EventRecorder {
+	r := &EventRecorder{
+		Timeout: DefaultTimeout,
+		t:       t,
+		updated: make(chan struct{}),
+		cursors: make(map[grpcbreaker.Key]int),
+	}
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				r.mu.Lock()
+				r.events = append(r.events, ev)
+				close(r.updated)
+				r.updated = make(chan struct{})
+				r.mu.Unlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+// Events returns all events recorded so far
+func (r *EventRecorder) Events() []grpcbreaker.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]grpcbreaker.Event(nil), r.events...)
+}
+
+// ExpectTransitions fails the test unless the breaker identified by key transitions through the given states in order,
+// starting from the first, e.g. ExpectTransitions(key, Closed, Open, HalfOpen). Each call picks up where the last
+// Expect call for the key left off.
+func (r *EventRecorder) ExpectTransitions(key grpcbreaker.Key, states ...grpcbreaker.State) {
+	r.t.Helper()
+
+	if len(states) < 2 {
+		r.t.Fatalf("ExpectTransitions requires at least two states but got %v", states)
+	}
+
+	r.expect(key, fmt.Sprintf("transitions through %v", states), func(ev grpcbreaker.Event) (bool, bool, error) {
+		e, ok := ev.(grpcbreaker.StateEvent)
+		if !ok || !e.Transition() {
+			return false, false, nil
+		}
+		if e.Old.State() != states[0] || e.New.State() != states[1] {
+			return false, false, fmt.Errorf(
+				"expected %v->%v but got %v->%v", states[0], states[1], e.Old.State(), e.New.State(),
+			)
+		}
+		states = states[1:]
+		return true, len(states) == 1, nil
+	})
+}
+
+// ExpectShed fails the test unless the breaker identified by key sheds a request. Each call picks up where the last
+// Expect call for the key left off.
+func (r *EventRecorder) ExpectShed(key grpcbreaker.Key) {
+	r.t.Helper()
+
+	r.expect(key, "a shed request", func(ev grpcbreaker.Event) (bool, bool, error) {
+		_, ok := ev.(grpcbreaker.ShedEvent)
+		return ok, ok, nil
+	})
+}
+
+// matcher inspects events in turn, reporting whether each matched and whether it's seen all it was waiting for
+type matcher func(grpcbreaker.Event) (matched, done bool, err error)
+
+// expect feeds the events of the given key to match until it's done, advancing that key's cursor past matches
+func (r *EventRecorder) expect(key grpcbreaker.Key, desc string, match matcher) {
+	r.t.Helper()
+
+	timeout := time.After(r.Timeout)
+	for {
+		done, updated, err := r.scan(key, match)
+		switch {
+		case err != nil:
+			r.t.Fatalf("%v: %v", key, err)
+		case done:
+			return
+		}
+
+		select {
+		case <-updated:
+		case <-timeout:
+			r.t.Fatalf("%v: timed out waiting for %v", key, desc)
+		}
+	}
+}
+
+func (r *EventRecorder) scan(key grpcbreaker.Key, match matcher) (bool, <-chan struct{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := r.cursors[key]; i < len(r.events); i++ {
+		if eventKey(r.events[i]) != key {
+			continue
+		}
+		matched, done, err := match(r.events[i])
+		if matched {
+			r.cursors[key] = i + 1
+		}
+		if done || err != nil {
+			return done, nil, err
+		}
+	}
+	return false, r.updated, nil
+}
+
+func eventKey(ev grpcbreaker.Event) grpcbreaker.Key {
+	switch e := ev.(type) {
+	case grpcbreaker.StateEvent:
+		return e.Key
+	case grpcbreaker.ShedEvent:
+		return e.Key
+	case grpcbreaker.EvictedEvent:
+		return e.Key
+	}
+	return grpcbreaker.Key{}
+}
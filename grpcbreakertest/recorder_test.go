@@ -0,0 +1,105 @@
+package grpcbreakertest
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+// fatalT records the failure of a test rather than failing it
+type fatalT struct {
+	testing.TB
+	failure string
+}
+
+func (t *fatalT) Helper() {}
+
+func (t *fatalT) Fatalf(format string, args ...interface{}) {
+	t.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// failure runs f on its own goroutine, as Fatalf must stop it, and returns the failure it reported, if any
+func failure(t *testing.T, f func(t testing.TB)) string {
+	ft := &fatalT{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(ft)
+	}()
+	<-done
+	return ft.failure
+}
+
+func transition(key grpcbreaker.Key, from, to grpcbreaker.State) grpcbreaker.StateEvent {
+	return grpcbreaker.StateEvent{Key: key, Old: grpcbreaker.GenState(from), New: grpcbreaker.GenState(to)}
+}
+
+func TestEventRecorder_ExpectTransitions(t *testing.T) {
+	global := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+	other := grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/pbtest.SvcA"}
+
+	events := make(chan grpcbreaker.Event, 10)
+	events <- transition(other, grpcbreaker.Closed, grpcbreaker.Open)
+	events <- transition(global, grpcbreaker.Closed, grpcbreaker.Closed) // not a transition
+	events <- transition(global, grpcbreaker.Closed, grpcbreaker.Open)
+	events <- grpcbreaker.ShedEvent{Key: global}
+	r := NewEventRecorder(t, events)
+
+	r.ExpectTransitions(global, grpcbreaker.Closed, grpcbreaker.Open)
+	r.ExpectShed(global)
+
+	// later events are waited for, picking up after those already matched
+	go func() {
+		events <- transition(global, grpcbreaker.Open, grpcbreaker.HalfOpen)
+		events <- transition(global, grpcbreaker.HalfOpen, grpcbreaker.Closed)
+	}()
+	r.ExpectTransitions(global, grpcbreaker.Open, grpcbreaker.HalfOpen, grpcbreaker.Closed)
+
+	if n := len(r.Events()); n != 6 {
+		t.Fatalf("expected 6 events but got %d", n)
+	}
+}
+
+func TestEventRecorder_failures(t *testing.T) {
+	global := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+
+	for _, c := range []struct {
+		name, expected string
+		expect         func(r *EventRecorder)
+	}{
+		{
+			"unexpected transition",
+			"expected Closed->HalfOpen but got Closed->Open",
+			func(r *EventRecorder) { r.ExpectTransitions(global, grpcbreaker.Closed, grpcbreaker.HalfOpen) },
+		},
+		{
+			"timeout",
+			"timed out waiting for a shed request",
+			func(r *EventRecorder) { r.ExpectShed(global) },
+		},
+		{
+			"too few states",
+			"requires at least two states",
+			func(r *EventRecorder) { r.ExpectTransitions(global, grpcbreaker.Open) },
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			events := make(chan grpcbreaker.Event, 1)
+			events <- transition(global, grpcbreaker.Closed, grpcbreaker.Open)
+
+			got := failure(t, func(tb testing.TB) {
+				r := NewEventRecorder(tb, events)
+				r.Timeout = 10 * time.Millisecond
+				c.expect(r)
+			})
+			if !strings.Contains(got, c.expected) {
+				t.Fatalf("expected a failure containing %q but got %q", c.expected, got)
+			}
+		})
+	}
+}
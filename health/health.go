@@ -0,0 +1,200 @@
+// Package health reports services NOT_SERVING through grpc health checking while the breakers of their critical
+// dependencies are open, so that load balancers route around a process which can't do useful work.
+package health
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Rule maps the state of critical breakers to the health of services
+type Rule struct {
+	// Services are the services reported NOT_SERVING, by the names used for health checks; "" is the whole server
+	Services []string
+	// Breakers are the keys of the critical breakers. Only breakers of their own count, so a Method key must have
+	// been configured with an option set.
+	Breakers []grpcbreaker.Key
+	// MinOpen is how many of the breakers must be Open for the services to be NOT_SERVING; zero means one
+	MinOpen int
+	// Grace is how long that many must have been Open before the services are NOT_SERVING, riding out brief trips
+	Grace time.Duration
+}
+
+// StatusSetter is implemented by *health.Server of google.golang.org/grpc/health
+type StatusSetter interface {
+	SetServingStatus(service string, status healthpb.HealthCheckResponse_ServingStatus)
+}
+
+// Watch sets the status of the rules' services from the breakers' transitions until ctx is done. Services start out
+// SERVING unless their rules are already broken, and are NOT_SERVING while any of their rules is broken.
+//
+// As it follows the breakers' transitions, a burst of more than fit in its buffer may be missed; it also reconciles
+// with the breakers' states every ReconcileInterval, so that it's never wrong for longer than that.
+func Watch(ctx context.Context, b *grpcbreaker.Breaker, hs StatusSetter, rules ...Rule) {
+	w := &watcher{
+		rules:    rules,
+		b:        b,
+		clock:    b.Clock(),
+		hs:       hs,
+		openedAt: make(map[grpcbreaker.Key]time.Time),
+		statuses: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+		wake:     make(chan struct{}, 1),
+	}
+
+	// subscribe before taking the initial state, so that no transitions are missed in between
+	events := b.SubscribeTransitions(ctx, 1000)
+	w.reconcile()
+	w.evaluate()
+
+	t := w.clock.NewTimer(ReconcileInterval) // created before returning so that fake clocks see it straight away
+	go w.run(events, t)
+}
+
+// ReconcileInterval is how often Watch checks the breakers' states, in case it missed any transitions
+const ReconcileInterval = 30 * time.Second
+
+type watcher struct {
+	rules []Rule
+	b     *grpcbreaker.Breaker
+	clock grpcbreaker.Clock
+	hs    StatusSetter
+
+	openedAt map[grpcbreaker.Key]time.Time // of each open breaker of interest
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	grace    grpcbreaker.Timer // for the earliest grace period still to run; nil until there's been one
+	wake     chan struct{}     // signalled when a grace period ends
+}
+
+func (w *watcher) run(events <-chan grpcbreaker.Event, reconcile grpcbreaker.Timer) {
+	defer reconcile.Stop()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				if w.grace != nil {
+					w.grace.Stop()
+				}
+				return
+			}
+			if e, isState := ev.(grpcbreaker.StateEvent); isState && e.Transition() {
+				if e.New.State() == grpcbreaker.Open {
+					w.openedAt[e.Key] = e.Published
+				} else {
+					delete(w.openedAt, e.Key)
+				}
+				w.evaluate()
+			}
+		case <-w.wake:
+			w.evaluate()
+		case <-reconcile.C():
+			w.reconcile()
+			w.evaluate()
+			reconcile.Reset(ReconcileInterval)
+		}
+	}
+}
+
+// reconcile sets which breakers of interest are open from their current states. The times they opened are kept if
+// known, else taken from their histories, else assumed to be now.
+func (w *watcher) reconcile() {
+	now := w.clock.Now()
+	for _, r := range w.rules {
+		for _, k := range r.Breakers {
+			s, ok := w.b.Get(k)
+			if !ok || s.State.State() != grpcbreaker.Open {
+				delete(w.openedAt, k)
+				continue
+			}
+			if t, ok := openedAt(s); ok {
+				w.openedAt[k] = t
+			} else if _, ok := w.openedAt[k]; !ok {
+				w.openedAt[k] = now
+			}
+		}
+	}
+}
+
+// openedAt returns when the breaker entered its current, open, state, if it's in its retained transitions
+func openedAt(s grpcbreaker.Snapshot) (time.Time, bool) {
+	for i := len(s.Transitions) - 1; i >= 0; i-- {
+		if t := s.Transitions[i]; t.New == s.State {
+			return t.Published, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// evaluate sets the status of every service, arranging to be woken when the earliest grace period still to run ends
+func (w *watcher) evaluate() {
+	now := w.clock.Now()
+
+	var next time.Time
+	statuses := make(map[string]healthpb.HealthCheckResponse_ServingStatus)
+	for _, r := range w.rules {
+		status := healthpb.HealthCheckResponse_SERVING
+		if brokenAt, ok := r.brokenAt(w.openedAt); ok {
+			if deadline := brokenAt.Add(r.Grace); !deadline.After(now) {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			} else if next.IsZero() || deadline.Before(next) {
+				next = deadline
+			}
+		}
+
+		for _, svc := range r.Services {
+			if statuses[svc] != healthpb.HealthCheckResponse_NOT_SERVING {
+				statuses[svc] = status
+			}
+		}
+	}
+
+	for svc, status := range statuses {
+		if cur, ok := w.statuses[svc]; !ok || cur != status {
+			w.statuses[svc] = status
+			w.hs.SetServingStatus(svc, status)
+		}
+	}
+
+	if next.IsZero() {
+		return
+	}
+	if w.grace == nil {
+		w.grace = w.clock.AfterFunc(next.Sub(now), w.signal)
+	} else {
+		w.grace.Reset(next.Sub(now))
+	}
+	if !w.clock.Now().Before(next) {
+		w.signal() // the clock moved on before the timer was set
+	}
+}
+
+func (w *watcher) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// brokenAt returns when enough of the rule's breakers were open to break it, if they are
+func (r Rule) brokenAt(openedAt map[grpcbreaker.Key]time.Time) (time.Time, bool) {
+	min := r.MinOpen
+	if min <= 0 {
+		min = 1
+	}
+
+	var times []time.Time
+	for _, k := range r.Breakers {
+		if t, ok := openedAt[k]; ok {
+			times = append(times, t)
+		}
+	}
+	if len(times) < min {
+		return time.Time{}, false
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times[min-1], true
+}
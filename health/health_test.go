@@ -0,0 +1,116 @@
+package health_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
+	"github.com/jwilner/grpcbreaker/health"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestWatch(t *testing.T) {
+	h := grpcbreakertest.NewBreaker(
+		t,
+		grpcbreaker.Global(),
+		grpcbreaker.Service("/payments.Svc"),
+		grpcbreaker.Service("/ledger.Svc"),
+	)
+	payments := grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/payments.Svc"}
+	ledger := grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/ledger.Svc"}
+
+	ctx, cncl := context.WithCancel(context.Background())
+	t.Cleanup(cncl)
+
+	hs := grpchealth.NewServer()
+	health.Watch(
+		ctx,
+		h.Breaker,
+		hs,
+		health.Rule{Services: []string{"checkout"}, Breakers: []grpcbreaker.Key{payments}, Grace: 10 * time.Second},
+		health.Rule{Services: []string{"reports"}, Breakers: []grpcbreaker.Key{payments, ledger}, MinOpen: 2},
+	)
+
+	expect := func(t *testing.T, svc string, want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			resp, err := hs.Check(ctx, &healthpb.HealthCheckRequest{Service: svc})
+			if err == nil && resp.Status == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%v: expected %v but got %v, %v", svc, want, resp, err)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	expect(t, "checkout", healthpb.HealthCheckResponse_SERVING)
+	expect(t, "reports", healthpb.HealthCheckResponse_SERVING)
+
+	// the grace period is measured from the transition, however late the watcher sees it
+	if err := h.Breaker.Override(payments, grpcbreaker.Open); err != nil {
+		t.Fatal(err)
+	}
+	h.Clock.Advance(10 * time.Second)
+	expect(t, "checkout", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect(t, "reports", healthpb.HealthCheckResponse_SERVING) // only one of two open
+
+	if err := h.Breaker.Override(ledger, grpcbreaker.Open); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "reports", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if err := h.Breaker.Override(payments, grpcbreaker.Closed); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, "checkout", healthpb.HealthCheckResponse_SERVING)
+	expect(t, "reports", healthpb.HealthCheckResponse_SERVING)
+}
+
+func TestWatch_alreadyOpen(t *testing.T) {
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(), grpcbreaker.Service("/payments.Svc"))
+	payments := grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/payments.Svc"}
+
+	if err := h.Breaker.Override(payments, grpcbreaker.Open); err != nil {
+		t.Fatal(err)
+	}
+	h.Clock.Advance(8 * time.Second)
+
+	ctx, cncl := context.WithCancel(context.Background())
+	t.Cleanup(cncl)
+
+	hs := grpchealth.NewServer()
+	health.Watch(
+		ctx,
+		h.Breaker,
+		hs,
+		health.Rule{Services: []string{"checkout"}, Breakers: []grpcbreaker.Key{payments}, Grace: 10 * time.Second},
+	)
+
+	check := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := hs.Check(ctx, &healthpb.HealthCheckRequest{Service: "checkout"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Status
+	}
+
+	if s := check(); s != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING during the grace period but got %v", s)
+	}
+
+	// the grace period runs from when the breaker opened, not from when the watch started
+	h.Clock.Advance(2 * time.Second)
+	deadline := time.Now().Add(time.Second)
+	for check() != healthpb.HealthCheckResponse_NOT_SERVING {
+		if time.Now().After(deadline) {
+			t.Fatal("expected NOT_SERVING once the breaker had been open for the grace period")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,143 @@
+package grpcbreaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shedSampleInterval is how often each breaker samples requests it sheds, beyond the first of each generation
+const shedSampleInterval = time.Second
+
+// History is the recent history of a breaker
+type History struct {
+	Key
+	// Transitions are the most recent transitions, oldest first
+	Transitions []StateEvent
+	// Shed is a sample of the most recent requests shed, oldest first: the first of each generation and then at most
+	// one a second
+	Shed []ShedSample
+	// ShedTotal is the number of requests shed over the breaker's lifetime
+	ShedTotal int
+}
+
+// ShedSample is a sampled ShedEvent
+type ShedSample struct {
+	ShedEvent
+	// Skipped is the number of requests shed since the previous sample
+	Skipped int
+}
+
+// History returns the recent history of the breaker with the given key, as many transitions and shed samples as its
+// HistorySize
+func (b *Breaker) History(key Key) (History, bool) {
+	br, ok := b.cache.find(key)
+	if !ok {
+		return History{}, false
+	}
+	return br.recentHistory()
+}
+
+func (br *breaker) recentHistory() (History, bool) {
+	if br.history == nil {
+		return History{}, false
+	}
+
+	h := br.history
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist := History{Key: br.Key, ShedTotal: int(atomic.LoadInt64(&h.sheds))}
+	for _, ev := range h.transitions.all() {
+		hist.Transitions = append(hist.Transitions, ev.(StateEvent))
+	}
+	for _, s := range h.shed.all() {
+		hist.Shed = append(hist.Shed, s.(ShedSample))
+	}
+	return hist, true
+}
+
+// history retains a breaker's recent transitions and a sample of the requests it's shed, so that they can be
+// inspected after the fact without having consumed Events. (gRPC keeps the equivalent for subchannels in channelz,
+// but offers no way for others to add to it.)
+type history struct {
+	sheds, lastSampleNanos int64  // atomic
+	lastSampleGen          uint64 // atomic, the GenState of the last sample
+
+	mu           sync.Mutex
+	transitions  eventRing
+	shed         eventRing
+	sampledTotal int64 // the value of sheds when last sampled
+}
+
+func newHistory(size int) *history {
+	return &history{transitions: eventRing{buf: make([]Event, size)}, shed: eventRing{buf: make([]Event, size)}}
+}
+
+func (h *history) addTransition(ev StateEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.transitions.add(ev)
+}
+
+// addShed counts a shed request, sampling it if it's the first of its generation or the interval has passed; it's
+// called for every request shed, so only takes the lock when sampling
+func (h *history) addShed(ev ShedEvent) {
+	total := atomic.AddInt64(&h.sheds, 1)
+	if !h.due(ev) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.due(ev) { // someone else sampled first
+		return
+	}
+
+	h.shed.add(ShedSample{ev, int(total - h.sampledTotal - 1)})
+	h.sampledTotal = total
+	atomic.StoreUint64(&h.lastSampleGen, uint64(ev.State))
+	atomic.StoreInt64(&h.lastSampleNanos, ev.Published.UnixNano())
+}
+
+func (h *history) due(ev ShedEvent) bool {
+	return GenState(atomic.LoadUint64(&h.lastSampleGen)) != ev.State ||
+		ev.Published.UnixNano()-atomic.LoadInt64(&h.lastSampleNanos) >= int64(shedSampleInterval)
+}
+
+// recentTransitions returns the retained transitions, oldest first
+func (h *history) recentTransitions() []StateEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := h.transitions.all()
+	transitions := make([]StateEvent, len(events))
+	for i, ev := range events {
+		transitions[i] = ev.(StateEvent)
+	}
+	return transitions
+}
+
+// eventRing retains the last len(buf) events added to it
+type eventRing struct {
+	buf  []Event
+	next int // the index of the next event to be overwritten
+	full bool
+}
+
+func (r *eventRing) add(ev Event) {
+	if len(r.buf) == 0 {
+		return
+	}
+	r.buf[r.next] = ev
+	r.next = (r.next + 1) % len(r.buf)
+	r.full = r.full || r.next == 0
+}
+
+// all returns the retained events, oldest first
+func (r *eventRing) all() []Event {
+	if !r.full {
+		return append([]Event(nil), r.buf[:r.next]...)
+	}
+	return append(append([]Event(nil), r.buf[r.next:]...), r.buf[:r.next]...)
+}
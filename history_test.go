@@ -0,0 +1,42 @@
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHistory(t *testing.T) {
+	b := newTestBreaker(t, FailThreshold(1), ResetTimeout(time.Second), HistorySize(3))
+	circuitNope := func(ctx context.Context) (metadata.MD, error) { return nil, errors.New("nope") }
+
+	_ = b.call(context.Background(), "", circuitNope)
+	b.clock.Advance(time.Second)
+	_ = b.call(context.Background(), "", circuitNope)
+	b.assertSequence(Closed, Open, HalfOpen, Open)
+
+	s, ok := b.snapshot()
+	if !ok {
+		t.Fatal("expected a snapshot")
+	}
+	if len(s.Transitions) != 3 {
+		t.Fatalf("expected 3 transitions but got %v", s.Transitions)
+	}
+
+	// the oldest is dropped once full
+	b.clock.Advance(time.Second)
+	s, _ = b.snapshot()
+	var got []State
+	for _, tr := range s.Transitions {
+		got = append(got, tr.New.State())
+	}
+	if want := []State{HalfOpen, Open, HalfOpen}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("expected transitions to %v but got %v", want, got)
+	}
+	if !s.Transitions[2].Published.Equal(time.Unix(2, 0)) || s.Transitions[1].Fails != 1 {
+		t.Fatalf("unexpected transitions %+v", s.Transitions)
+	}
+}
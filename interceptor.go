@@ -2,21 +2,67 @@ package grpcbreaker
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/jwilner/grpcbreaker/internal/clock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type Breaker struct {
 	UnaryInterceptor grpc.UnaryClientInterceptor
 	Events           <-chan Event
+
+	cache *cache
 }
 
-func New(ctx context.Context, g *GlobalOptionSet, optionSets ...*OptionSet) *Breaker {
-	defaults := []Option{
+// defaultOptions configure breakers before the options given for them, whether of a Breaker or a Circuit
+func defaultOptions() []Option {
+	return []Option{
 		Predicate(func(error) bool { return true }),
+		MaxPartitions(1000),
+		MaxCallSites(10000),
+		MaxHosts(1000),
+		MaxPatternBreakers(1000),
+		IdleTTL(10 * time.Minute),
+		HistorySize(32),
+		Causes(5),
+		WithClock(clock.Real{}),
+	}
+}
+
+// New returns a Breaker configured by the option sets, or an error if they're invalid, e.g. if two patterns overlap
+func New(ctx context.Context, g *GlobalOptionSet, optionSets ...*OptionSet) (*Breaker, error) {
+	defaults := defaultOptions()
+
+	var global settings
+	for _, o := range append(defaults, g.options...) {
+		o(&global)
+	}
+
+	if global.persistence.store != nil && global.persistence.interval <= 0 {
+		return nil, fmt.Errorf("persist interval must be positive but is %v", global.persistence.interval)
 	}
+	if global.sharing.state != nil && global.sharing.interval <= 0 {
+		return nil, fmt.Errorf("share interval must be positive but is %v", global.sharing.interval)
+	}
+
 	monitorCh := make(chan Event, 100)
-	bc := newCache(deps{ctx.Done(), monitorCh}, defaults, g, optionSets...)
+	deps := newDeps(ctx.Done(), monitorCh, global.globalClock)
+	if global.persistence.store != nil {
+		deps.restored = loadCheckpoints(ctx, global.persistence, deps)
+	}
+	bc, err := newCache(deps, defaults, g, optionSets...)
+	if err != nil {
+		return nil, err
+	}
+	if global.persistence.store != nil {
+		bc.checkpointEvery(ctx, global.persistence)
+	}
+	if global.sharing.state != nil {
+		bc.shareEvery(ctx, global.sharing)
+	}
 
 	interceptor := func(
 		ctx context.Context,
@@ -26,11 +72,13 @@ func New(ctx context.Context, g *GlobalOptionSet, optionSets ...*OptionSet) *Bre
 		invoker grpc.UnaryInvoker,
 		opts ...grpc.CallOption,
 	) error {
-		b := bc.resolve(method, opts)
-		return b.call(ctx, func(ctx context.Context) error {
-			return invoker(ctx, method, req, reply, cc, opts...)
+		b := bc.resolve(method, opts).partition(ctx, method, req)
+		return b.call(ctx, method, func(ctx context.Context) (metadata.MD, error) {
+			var trailer metadata.MD
+			err := invoker(ctx, method, req, reply, cc, append(opts[:len(opts):len(opts)], grpc.Trailer(&trailer))...)
+			return trailer, err
 		})
 	}
 
-	return &Breaker{interceptor, monitorCh}
+	return &Breaker{UnaryInterceptor: interceptor, Events: monitorCh, cache: bc}, nil
 }
@@ -0,0 +1,152 @@
+// Package clock abstracts the passage of time so that grpcbreaker can be tested deterministically
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates timers
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is the subset of *time.Timer's behavior used by grpcbreaker
+type Timer interface {
+	// C returns the channel on which the time is delivered; it is nil for timers created by AfterFunc
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Real is the Clock backed by the time package
+type Real struct{}
+
+// Now returns time.Now()
+func (Real) Now() time.Time { return time.Now() }
+
+// NewTimer wraps time.NewTimer
+func (Real) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+// AfterFunc wraps time.AfterFunc
+func (Real) AfterFunc(d time.Duration, f func()) Timer { return realTimer{time.AfterFunc(d, f)} }
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+// Fake is a Clock which only moves when told to. Timers fire synchronously within the call to Advance or Set which
+// passes their deadlines, in deadline order.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake returns a Fake set to the given time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake's current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTimer returns a timer which delivers the fake time on its channel once the fake passes its deadline
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: f, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc returns a timer which calls fn once the fake passes its deadline
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &fakeTimer{clock: f, f: fn}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the fake forward by d, firing any timers whose deadlines are passed
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the fake to the given time, firing any timers whose deadlines are passed; the fake never moves backward
+func (f *Fake) Set(target time.Time) {
+	for {
+		f.mu.Lock()
+		if len(f.timers) == 0 || f.timers[0].when.After(target) {
+			if target.After(f.now) {
+				f.now = target
+			}
+			f.mu.Unlock()
+			return
+		}
+
+		t := f.timers[0]
+		f.timers = f.timers[1:]
+		if t.when.After(f.now) {
+			f.now = t.when
+		}
+		now := f.now
+		f.mu.Unlock()
+
+		// fire without holding the lock, as the timer's function may well use the clock
+		if t.f != nil {
+			t.f()
+			continue
+		}
+		select {
+		case t.c <- now:
+		default:
+		}
+	}
+}
+
+// removeLocked unschedules the timer, returning true if it was scheduled; f.mu must be held
+func (f *Fake) removeLocked(t *fakeTimer) bool {
+	for i, o := range f.timers {
+		if o == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock *Fake
+	when  time.Time
+	c     chan time.Time
+	f     func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.removeLocked(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	f := t.clock
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	active := f.removeLocked(t)
+	t.when = f.now.Add(d)
+	i := sort.Search(len(f.timers), func(i int) bool { return f.timers[i].when.After(t.when) })
+	f.timers = append(f.timers, nil)
+	copy(f.timers[i+1:], f.timers[i:])
+	f.timers[i] = t
+	return active
+}
@@ -10,7 +10,9 @@ type StateEvent struct {
 	Key
 	Old, New                         GenState
 	Published, LastFail, ResetMoment time.Time
-	Fails, Passes                    int
+	Fails, Passes, Ignored           int
+	FailScore                        float64 // the sum of the weights of Fails
+	Causes                           []Cause // for transitions, the most recent failures of the Old state
 }
 
 // Transition is true if this event represents a state transition
@@ -27,6 +29,24 @@ type ShedEvent struct {
 
 func (ShedEvent) isEvent() {}
 
+// EvictedEvent is published when a dynamically created breaker is stopped and removed, either because it was idle
+// or to make room for others
+type EvictedEvent struct {
+	Key
+	Published, LastUsed time.Time
+}
+
+func (EvictedEvent) isEvent() {}
+
+// ErrorEvent is published when work done by the breakers in the background fails, e.g. saving checkpoints
+type ErrorEvent struct {
+	Published time.Time
+	Op        string // what failed, e.g. "save checkpoints"
+	Err       error
+}
+
+func (ErrorEvent) isEvent() {}
+
 // Event is an observability event published by the breaker
 type Event interface {
 	isEvent()
@@ -39,11 +59,22 @@ func LogEvents(ctx context.Context, logF func(format string, args ...interface{}
 			case ev := <-events:
 				switch t := ev.(type) {
 				case StateEvent:
-					if t.Transition() {
+					switch {
+					case t.Transition() && len(t.Causes) > 0:
+						last := t.Causes[len(t.Causes)-1]
+						logF(
+							"%v transitioned from %v to %v after %d failures, the last %v: %v from %v",
+							t.Key, t.Old.State(), t.New.State(), t.Fails, last.Code, last.Message, last.Method,
+						)
+					case t.Transition():
 						logF("%v transitioned from %v to %v", t.Key, t.Old.State(), t.New.State())
 					}
 				case ShedEvent:
 					logF("%v shed a request", t.Key)
+				case EvictedEvent:
+					logF("%v evicted after last use at %v", t.Key, t.LastUsed)
+				case ErrorEvent:
+					logF("failed to %v: %v", t.Op, t.Err)
 				}
 			case <-ctx.Done():
 				return
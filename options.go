@@ -1,6 +1,7 @@
 package grpcbreaker
 
 import (
+	"context"
 	"time"
 
 	"google.golang.org/grpc"
@@ -13,19 +14,34 @@ type GlobalOptionSet struct {
 type OptionSet struct {
 	key     Key
 	options []Option
+	pattern *pattern // set if the option set applies to every name matching key.Name, rather than to it alone
 }
 
 type settings struct {
-	predicate                     func(error) bool
+	classifier                    Classifier
 	reset                         time.Duration
 	failThreshold, resetThreshold int
+	keyFunc                       func(ctx context.Context, method string, req interface{}) string
+	maxPartitions, maxCallSites   int
+	maxHosts, maxPatternBreakers  int
+	idleTTL                       time.Duration
+	globalClock                   Clock       // copied into deps by New
+	persistence                   persistence // only read by New
+	sharing                       sharing     // only read by New
+	quorum                        quorum
+	historySize, maxCauses        int
+	redact                        func(Cause) Cause
 }
 
 type Option func(*settings)
 
 func Predicate(predicate func(error) bool) Option {
+	return Classify(predicateClassifier(predicate))
+}
+
+func Classify(classifier Classifier) Option {
 	return func(s *settings) {
-		s.predicate = predicate
+		s.classifier = classifier
 	}
 }
 
@@ -47,23 +63,143 @@ func ResetThreshold(threshold int) Option {
 	}
 }
 
+func KeyFunc(keyFunc func(ctx context.Context, method string, req interface{}) string) Option {
+	return func(s *settings) {
+		s.keyFunc = keyFunc
+	}
+}
+
+func MaxPartitions(max int) Option {
+	return func(s *settings) {
+		s.maxPartitions = max
+	}
+}
+
+func IdleTTL(ttl time.Duration) Option {
+	return func(s *settings) {
+		s.idleTTL = ttl
+	}
+}
+
+func MaxCallSites(max int) Option {
+	return func(s *settings) {
+		s.maxCallSites = max
+	}
+}
+
+// MaxHosts bounds the number of Host breakers created for hosts without option sets, beyond which HTTP requests to
+// further hosts share a single Host breaker; it's only respected in the Global option set
+func MaxHosts(max int) Option {
+	return func(s *settings) {
+		s.maxHosts = max
+	}
+}
+
+// MaxPatternBreakers bounds the number of breakers created for names matching ServiceGlob, ServiceRegexp, MethodGlob
+// and MethodRegexp option sets, beyond which further names share their parent's breaker until idle ones are evicted;
+// it's only respected in the Global option set
+func MaxPatternBreakers(max int) Option {
+	return func(s *settings) {
+		s.maxPatternBreakers = max
+	}
+}
+
+// WithClock sets the Clock used by all breakers; it's only respected in the Global option set
+func WithClock(clock Clock) Option {
+	return func(s *settings) {
+		s.globalClock = clock
+	}
+}
+
+// Persist checkpoints the state of all breakers to the store at the given interval, and on startup restores breakers
+// from checkpoints saved no more than staleness ago; it's only respected in the Global option set, and New returns an
+// error unless the interval is positive
+func Persist(store StateStore, interval, staleness time.Duration) Option {
+	return func(s *settings) {
+		s.persistence = persistence{store, interval, staleness}
+	}
+}
+
+// Share publishes the state of all breakers to the shared state as the given peer at the given interval, learning
+// peers' states in return; it's only respected in the Global option set, and New returns an error unless the interval
+// is positive
+func Share(state SharedState, peer string, interval time.Duration) Option {
+	return func(s *settings) {
+		s.sharing = sharing{state, peer, interval}
+	}
+}
+
+// PeerQuorum opens a Closed breaker once at least n of its peers, and at least the given fraction of them, report it
+// Open; it has no effect without Share
+func PeerQuorum(n int, fraction float64) Option {
+	return func(s *settings) {
+		s.quorum = quorum{n, fraction}
+	}
+}
+
+// HistorySize sets how many of its most recent transitions, and of its samples of shed requests, each breaker retains
+// for Snapshot and History
+func HistorySize(n int) Option {
+	return func(s *settings) {
+		s.historySize = n
+	}
+}
+
+// Causes sets how many of the most recent failures each breaker retains in each state, to be reported on transitions
+// and in snapshots
+func Causes(n int) Option {
+	return func(s *settings) {
+		s.maxCauses = n
+	}
+}
+
+// RedactCauses sets a function applied to each failure before it's retained, e.g. to strip personal data from messages
+func RedactCauses(redact func(Cause) Cause) Option {
+	return func(s *settings) {
+		s.redact = redact
+	}
+}
+
 type CallOption struct {
 	optionSet OptionSet
 	grpc.EmptyCallOption
 }
 
 func CallSite(name string, opts ...Option) *CallOption {
-	return &CallOption{optionSet: OptionSet{Key{BreakerCallSite, name}, opts}}
+	return &CallOption{optionSet: OptionSet{key: Key{Type: BreakerCallSite, Name: name}, options: opts}}
 }
 
 func Method(name string, opts ...Option) *OptionSet {
-	return &OptionSet{Key{BreakerMethod, name}, opts}
+	return &OptionSet{key: Key{Type: BreakerMethod, Name: name}, options: opts}
+}
+
+// Package returns an option set for every service in the proto package and any nested packages, named as the prefix
+// of their services' names, e.g. "/billing.v1" for "/billing.v1.Invoices". Services without option sets of their own
+// share the breaker of their innermost configured package.
+func Package(name string, opts ...Option) *OptionSet {
+	return &OptionSet{key: Key{Type: BreakerPackage, Name: name}, options: opts}
 }
 
 func Service(name string, opts ...Option) *OptionSet {
-	return &OptionSet{Key{BreakerService, name}, opts}
+	return &OptionSet{key: Key{Type: BreakerService, Name: name}, options: opts}
+}
+
+// Host returns an option set for HTTP requests to the host, e.g. "api.example.com" or "localhost:8080"
+func Host(name string, opts ...Option) *OptionSet {
+	return &OptionSet{key: Key{Type: BreakerHost, Name: name}, options: opts}
+}
+
+// Path returns an option set for HTTP requests to a host under a path, e.g. "api.example.com/v1/users"; requests
+// resolve to the longest configured path which is a whole number of segments of their own, else to their host
+func Path(name string, opts ...Option) *OptionSet {
+	return &OptionSet{key: Key{Type: BreakerPath, Name: name}, options: opts}
 }
 
 func Global(opts ...Option) *GlobalOptionSet {
 	return &GlobalOptionSet{opts}
 }
+
+// With returns a copy of the global option set with the given options appended, overriding any earlier ones
+func (g *GlobalOptionSet) With(opts ...Option) *GlobalOptionSet {
+	return &GlobalOptionSet{append(g.options[:len(g.options):len(g.options)], opts...)}
+}
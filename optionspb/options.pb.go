@@ -0,0 +1,261 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.15.5
+// source: grpcbreaker/options.proto
+
+// Options with which API owners declare breaker policy alongside their services. protoc-gen-grpcbreaker turns them
+// into the matching grpcbreaker.Service and grpcbreaker.Method option sets.
+
+package optionspb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	duration "github.com/golang/protobuf/ptypes/duration"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Policy is the breaker policy of a service or method; unset fields are inherited, from the service in the case of
+// a method and from the Global option set in the case of a service
+type Policy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// fail_threshold is the grpcbreaker.FailThreshold
+	FailThreshold uint32 `protobuf:"varint,1,opt,name=fail_threshold,json=failThreshold,proto3" json:"fail_threshold,omitempty"`
+	// reset_timeout is the grpcbreaker.ResetTimeout
+	ResetTimeout *duration.Duration `protobuf:"bytes,2,opt,name=reset_timeout,json=resetTimeout,proto3" json:"reset_timeout,omitempty"`
+	// failure_codes are the names of the status codes counted as failures, e.g. "UNAVAILABLE"
+	FailureCodes []string `protobuf:"bytes,3,rep,name=failure_codes,json=failureCodes,proto3" json:"failure_codes,omitempty"`
+	// fallbackable declares that callers can degrade gracefully when the breaker is open
+	Fallbackable bool `protobuf:"varint,4,opt,name=fallbackable,proto3" json:"fallbackable,omitempty"`
+	// idempotent declares that calls can safely be retried
+	Idempotent bool `protobuf:"varint,5,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcbreaker_options_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcbreaker_options_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_grpcbreaker_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Policy) GetFailThreshold() uint32 {
+	if x != nil {
+		return x.FailThreshold
+	}
+	return 0
+}
+
+func (x *Policy) GetResetTimeout() *duration.Duration {
+	if x != nil {
+		return x.ResetTimeout
+	}
+	return nil
+}
+
+func (x *Policy) GetFailureCodes() []string {
+	if x != nil {
+		return x.FailureCodes
+	}
+	return nil
+}
+
+func (x *Policy) GetFallbackable() bool {
+	if x != nil {
+		return x.Fallbackable
+	}
+	return false
+}
+
+func (x *Policy) GetIdempotent() bool {
+	if x != nil {
+		return x.Idempotent
+	}
+	return false
+}
+
+var file_grpcbreaker_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptor.ServiceOptions)(nil),
+		ExtensionType: (*Policy)(nil),
+		Field:         52100,
+		Name:          "grpcbreaker.service_policy",
+		Tag:           "bytes,52100,opt,name=service_policy",
+		Filename:      "grpcbreaker/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptor.MethodOptions)(nil),
+		ExtensionType: (*Policy)(nil),
+		Field:         52100,
+		Name:          "grpcbreaker.method_policy",
+		Tag:           "bytes,52100,opt,name=method_policy",
+		Filename:      "grpcbreaker/options.proto",
+	},
+}
+
+// Extension fields to descriptor.ServiceOptions.
+var (
+	// optional grpcbreaker.Policy service_policy = 52100;
+	E_ServicePolicy = &file_grpcbreaker_options_proto_extTypes[0]
+)
+
+// Extension fields to descriptor.MethodOptions.
+var (
+	// optional grpcbreaker.Policy method_policy = 52100;
+	E_MethodPolicy = &file_grpcbreaker_options_proto_extTypes[1]
+)
+
+var File_grpcbreaker_options_proto protoreflect.FileDescriptor
+
+var file_grpcbreaker_options_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2f, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x67, 0x72, 0x70,
+	0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd8, 0x01, 0x0a, 0x06, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x74, 0x68,
+	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x66,
+	0x61, 0x69, 0x6c, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x3e, 0x0a, 0x0d,
+	0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c,
+	0x72, 0x65, 0x73, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x23, 0x0a, 0x0d,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65,
+	0x73, 0x12, 0x22, 0x0a, 0x0c, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x61, 0x62, 0x6c,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74,
+	0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6d, 0x70,
+	0x6f, 0x74, 0x65, 0x6e, 0x74, 0x3a, 0x5d, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x84, 0x97, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x3a, 0x5a, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x5f, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x84, 0x97, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x52, 0x0c, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a,
+	0x77, 0x69, 0x6c, 0x6e, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x72, 0x65, 0x61, 0x6b,
+	0x65, 0x72, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70, 0x62, 0x3b, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_grpcbreaker_options_proto_rawDescOnce sync.Once
+	file_grpcbreaker_options_proto_rawDescData = file_grpcbreaker_options_proto_rawDesc
+)
+
+func file_grpcbreaker_options_proto_rawDescGZIP() []byte {
+	file_grpcbreaker_options_proto_rawDescOnce.Do(func() {
+		file_grpcbreaker_options_proto_rawDescData = protoimpl.X.CompressGZIP(file_grpcbreaker_options_proto_rawDescData)
+	})
+	return file_grpcbreaker_options_proto_rawDescData
+}
+
+var file_grpcbreaker_options_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_grpcbreaker_options_proto_goTypes = []interface{}{
+	(*Policy)(nil),                    // 0: grpcbreaker.Policy
+	(*duration.Duration)(nil),         // 1: google.protobuf.Duration
+	(*descriptor.ServiceOptions)(nil), // 2: google.protobuf.ServiceOptions
+	(*descriptor.MethodOptions)(nil),  // 3: google.protobuf.MethodOptions
+}
+var file_grpcbreaker_options_proto_depIdxs = []int32{
+	1, // 0: grpcbreaker.Policy.reset_timeout:type_name -> google.protobuf.Duration
+	2, // 1: grpcbreaker.service_policy:extendee -> google.protobuf.ServiceOptions
+	3, // 2: grpcbreaker.method_policy:extendee -> google.protobuf.MethodOptions
+	0, // 3: grpcbreaker.service_policy:type_name -> grpcbreaker.Policy
+	0, // 4: grpcbreaker.method_policy:type_name -> grpcbreaker.Policy
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	3, // [3:5] is the sub-list for extension type_name
+	1, // [1:3] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_grpcbreaker_options_proto_init() }
+func file_grpcbreaker_options_proto_init() {
+	if File_grpcbreaker_options_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_grpcbreaker_options_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Policy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_grpcbreaker_options_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_grpcbreaker_options_proto_goTypes,
+		DependencyIndexes: file_grpcbreaker_options_proto_depIdxs,
+		MessageInfos:      file_grpcbreaker_options_proto_msgTypes,
+		ExtensionInfos:    file_grpcbreaker_options_proto_extTypes,
+	}.Build()
+	File_grpcbreaker_options_proto = out.File
+	file_grpcbreaker_options_proto_rawDesc = nil
+	file_grpcbreaker_options_proto_goTypes = nil
+	file_grpcbreaker_options_proto_depIdxs = nil
+}
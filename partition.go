@@ -0,0 +1,114 @@
+package grpcbreaker
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// partitions holds the lazily created child breakers of a partitioned breaker, evicting the least recently used
+// when there are more than max
+type partitions struct {
+	mu  sync.Mutex
+	max int
+	lru *list.List // of *breaker, most recently used at the front
+	m   map[string]*list.Element
+}
+
+func newPartitions(max int) *partitions {
+	return &partitions{max: max, lru: list.New(), m: make(map[string]*list.Element)}
+}
+
+// partition returns the child breaker for the partition of this call, or the breaker itself if it is not partitioned
+// or the call doesn't belong to any partition
+func (b *breaker) partition(ctx context.Context, method string, req interface{}) *breaker {
+	if b.partitions == nil {
+		return b
+	}
+	name := b.keyFunc(ctx, method, req)
+	if name == "" {
+		return b
+	}
+
+	p := b.partitions
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e, ok := p.m[name]; ok {
+		p.lru.MoveToFront(e)
+		return e.Value.(*breaker)
+	}
+
+	s := b.settings
+	s.keyFunc = nil // partitions are not themselves partitioned
+
+	child := newBreaker(Key{Type: b.Type, Name: b.Name, Partition: name}, b.deps, s)
+	child.onIdle = func() { p.remove(child) }
+	child.start()
+	p.m[name] = p.lru.PushFront(child)
+
+	if p.max > 0 && p.lru.Len() > p.max {
+		evicted := p.lru.Back().Value.(*breaker)
+		p.removeLocked(evicted)
+		evicted.stop()
+		b.publish(EvictedEvent{evicted.Key, b.clock.Now(), evicted.lastUsed()})
+	}
+
+	return child
+}
+
+// lookup returns the named partition without creating it or counting it as used
+func (p *partitions) lookup(name string) (*breaker, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e, ok := p.m[name]; ok {
+		return e.Value.(*breaker), true
+	}
+	return nil, false
+}
+
+func (p *partitions) all() []*breaker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	all := make([]*breaker, 0, len(p.m))
+	for _, e := range p.m {
+		all = append(all, e.Value.(*breaker))
+	}
+	return all
+}
+
+func (p *partitions) stopAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.m {
+		e.Value.(*breaker).stop()
+	}
+}
+
+func (p *partitions) remove(b *breaker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removeLocked(b)
+}
+
+func (p *partitions) removeLocked(b *breaker) {
+	// the partition may already have been evicted and replaced, in which case there's nothing to do
+	if e, ok := p.m[b.Partition]; ok && e.Value.(*breaker) == b {
+		p.lru.Remove(e)
+		delete(p.m, b.Partition)
+	}
+}
+
+// OutgoingMetadata returns a function for use with KeyFunc which partitions calls by the first value of the given
+// outgoing metadata key
+func OutgoingMetadata(key string) func(ctx context.Context, method string, req interface{}) string {
+	return func(ctx context.Context, _ string, _ interface{}) string {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if vals := md.Get(key); len(vals) > 0 {
+			return vals[0]
+		}
+		return ""
+	}
+}
@@ -0,0 +1,274 @@
+package grpcbreaker
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// ServiceGlob returns an option set for every service whose name matches the glob, in which "*" matches any run of
+// characters other than "/", "?" matches any one of them, and all else is literal; e.g. "/billing.v1.*" matches every
+// service in the package billing.v1. Unlike Package, "*" isn't confined to a segment of the package name, as "." is
+// just another character: "/billing.*" matches "/billing.v1.internal.Svc" as well as "/billing.Svc".
+//
+// A service with a Service option set of its own is configured by that alone; otherwise, the options of the pattern
+// it matches, if any, are applied over those of its package, else the Global options. No service may match two
+// patterns: New returns an error if any two ServiceGlob or ServiceRegexp patterns could match the same name. Each
+// matching name gets a breaker of its own when first called, which is evicted once idle; see MaxPatternBreakers.
+func ServiceGlob(pattern string, opts ...Option) *OptionSet {
+	return &OptionSet{key: Key{Type: BreakerService, Name: pattern}, options: opts, pattern: compileGlob(pattern)}
+}
+
+// ServiceRegexp is ServiceGlob for a regular expression, which must match the whole service name
+func ServiceRegexp(expr string, opts ...Option) *OptionSet {
+	return &OptionSet{key: Key{Type: BreakerService, Name: expr}, options: opts, pattern: compileRegexp(expr)}
+}
+
+// MethodGlob returns an option set for every method whose full name matches the glob, as described for ServiceGlob;
+// e.g. "/billing.v1.Invoices/List*" matches every List method of the service.
+//
+// A method with a Method option set of its own is configured by that alone; otherwise, the options of the pattern it
+// matches, if any, are applied over those of its service. No method may match two patterns: New returns an error if
+// any two MethodGlob or MethodRegexp patterns could match the same name.
+func MethodGlob(pattern string, opts ...Option) *OptionSet {
+	return &OptionSet{key: Key{Type: BreakerMethod, Name: pattern}, options: opts, pattern: compileGlob(pattern)}
+}
+
+// MethodRegexp is MethodGlob for a regular expression, which must match the whole method name
+func MethodRegexp(expr string, opts ...Option) *OptionSet {
+	return &OptionSet{key: Key{Type: BreakerMethod, Name: expr}, options: opts, pattern: compileRegexp(expr)}
+}
+
+// pattern matches the names of the services or methods configured by an option set
+type pattern struct {
+	re   *regexp.Regexp
+	prog *syntax.Prog // of re, for detecting overlaps
+	err  error        // reported by New
+}
+
+func compileGlob(glob string) *pattern {
+	var expr strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			expr.WriteString("[^/]*")
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return compileRegexp(expr.String())
+}
+
+func compileRegexp(expr string) *pattern {
+	expr = "^(?:" + expr + ")$"
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return &pattern{err: err}
+	}
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return &pattern{err: err}
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return &pattern{err: err}
+	}
+	return &pattern{re: re, prog: prog}
+}
+
+// validatePatterns returns an error if any pattern failed to compile, or if two patterns for the same type of breaker
+// could match the same name
+func validatePatterns(sets []*OptionSet) error {
+	for i, a := range sets {
+		if a.pattern.err != nil {
+			return fmt.Errorf("%v pattern %q: %w", a.key.Type, a.key.Name, a.pattern.err)
+		}
+		for _, b := range sets[:i] {
+			if a.key.Type == b.key.Type && overlaps(a.pattern.prog, b.pattern.prog) {
+				return fmt.Errorf("%v patterns %q and %q overlap", a.key.Type, b.key.Name, a.key.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// overlaps reports whether some string is matched in full by both programs, by searching the product of their
+// automata. Empty-width assertions are assumed to hold, so patterns relying on them may be reported as overlapping when
+// they don't.
+func overlaps(a, b *syntax.Prog) bool {
+	type pos struct{ a, b uint32 }
+
+	seen := map[pos]bool{}
+	queue := []pos{{uint32(a.Start), uint32(b.Start)}}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		as, bs := closure(a, p.a), closure(b, p.b)
+		for _, ia := range as {
+			for _, ib := range bs {
+				ina, inb := &a.Inst[ia], &b.Inst[ib]
+				switch {
+				case ina.Op == syntax.InstMatch && inb.Op == syntax.InstMatch:
+					return true
+				case ina.Op != syntax.InstMatch && inb.Op != syntax.InstMatch && intersect(runes(ina), runes(inb)):
+					queue = append(queue, pos{ina.Out, inb.Out})
+				}
+			}
+		}
+	}
+	return false
+}
+
+// closure returns the instructions consuming a rune, or matching, reachable from pc without consuming a rune
+func closure(prog *syntax.Prog, pc uint32) []uint32 {
+	var (
+		found []uint32
+		seen  = map[uint32]bool{}
+		visit func(pc uint32)
+	)
+	visit = func(pc uint32) {
+		if seen[pc] {
+			return
+		}
+		seen[pc] = true
+
+		switch inst := &prog.Inst[pc]; inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			visit(inst.Out)
+			visit(inst.Arg)
+		case syntax.InstCapture, syntax.InstNop, syntax.InstEmptyWidth:
+			visit(inst.Out)
+		case syntax.InstFail:
+		default: // InstMatch and the InstRune family
+			found = append(found, pc)
+		}
+	}
+	visit(pc)
+	return found
+}
+
+// runes returns the ranges of runes consumed by the instruction, as pairs of inclusive bounds
+func runes(inst *syntax.Inst) []rune {
+	switch inst.Op {
+	case syntax.InstRuneAny:
+		return []rune{0, unicode.MaxRune}
+	case syntax.InstRuneAnyNotNL:
+		return []rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}
+	}
+
+	if len(inst.Rune) == 1 {
+		r0 := inst.Rune[0]
+		rs := []rune{r0, r0}
+		if syntax.Flags(inst.Arg)&syntax.FoldCase != 0 {
+			for r := unicode.SimpleFold(r0); r != r0; r = unicode.SimpleFold(r) {
+				rs = append(rs, r, r)
+			}
+		}
+		return rs
+	}
+	return inst.Rune
+}
+
+func intersect(a, b []rune) bool {
+	for i := 0; i+1 < len(a); i += 2 {
+		for j := 0; j+1 < len(b); j += 2 {
+			if a[i] <= b[j+1] && b[j] <= a[i+1] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// servicePatterns returns option sets for the services of the Method option sets which have no Service option set but
+// match a service pattern
+func servicePatterns(exact, patterns []*OptionSet) []*OptionSet {
+	services := make(map[string]bool)
+	for _, os := range exact {
+		if os.key.Type == BreakerService {
+			services[os.key.Name] = true
+		}
+	}
+
+	var matched []*OptionSet
+	for _, os := range exact {
+		if os.key.Type != BreakerMethod || os.key.Name == "" {
+			continue
+		}
+		idx := strings.Index(os.key.Name[1:], "/") + 1
+		if idx < 1 || services[os.key.Name[:idx]] {
+			continue
+		}
+		name := os.key.Name[:idx]
+		for _, p := range patterns {
+			if p.key.Type == BreakerService && p.pattern.re.MatchString(name) {
+				services[name] = true
+				matched = append(matched, &OptionSet{key: Key{Type: BreakerService, Name: name}, options: p.options})
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// matchPattern returns the breaker of the key, creating it if the key matches one of the patterns, configured by the
+// pattern's options over the parent's settings, or otherwise forwarding the key to the parent if forward is set. Like
+// call sites, these breakers are evicted once idle, and beyond MaxPatternBreakers of them the parent is shared for
+// now. It reports whether the breaker returned is stored under the key.
+func (bc *cache) matchPattern(key Key, parent *breaker, forward bool) (*breaker, bool) {
+	for _, p := range bc.patterns {
+		if p.key.Type != key.Type || !p.pattern.re.MatchString(key.Name) {
+			continue
+		}
+		if max := bc.global.maxPatternBreakers; max > 0 && atomic.LoadInt64(&bc.matched) >= int64(max) {
+			return parent, false // too many; rather than grow without bound, share the parent's breaker
+		}
+
+		s := parent.settings
+		for _, o := range p.options {
+			o(&s)
+		}
+		nb := newBreaker(key, parent.deps, s)
+		nb.onIdle = func() {
+			bc.forget(nb)
+			atomic.AddInt64(&bc.matched, -1)
+		}
+
+		b, loaded := bc.loadOrStore(key, nb)
+		if !loaded {
+			atomic.AddInt64(&bc.matched, 1)
+			b.start()
+		}
+		return b, true
+	}
+
+	if !forward {
+		return parent, false
+	}
+	b, _ := bc.loadOrStore(key, parent)
+	if b.onIdle != nil && b.load() == stopped {
+		bc.forget(b) // evicted as we forwarded to it, so take the forward back
+		return b, false
+	}
+	return b, true
+}
+
+// forget deletes the breaker from the cache, along with the keys forwarded to it, e.g. a service's methods
+func (bc *cache) forget(b *breaker) {
+	bc.m.Range(func(k, v interface{}) bool {
+		if v.(*breaker) == b {
+			bc.m.Delete(k)
+		}
+		return true
+	})
+}
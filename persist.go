@@ -0,0 +1,184 @@
+package grpcbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Checkpoint is the persisted state of a single breaker
+type Checkpoint struct {
+	Key                    Key
+	State                  GenState
+	Fails, Passes, Ignored int
+	FailScore              float64
+	LastFail, ResetMoment  time.Time
+	Saved                  time.Time
+}
+
+// StateStore persists checkpoints of breaker state so that, after a restart, breakers resume where they left off
+// rather than all coming back Closed
+type StateStore interface {
+	Save(ctx context.Context, checkpoints []Checkpoint) error
+	Load(ctx context.Context) ([]Checkpoint, error)
+}
+
+// FileStore is a StateStore keeping checkpoints in a JSON file, which is replaced atomically on each save
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a FileStore using the file at path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path}
+}
+
+// Save replaces the file's checkpoints
+func (f *FileStore) Save(_ context.Context, checkpoints []Checkpoint) error {
+	b, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// Load returns the file's checkpoints, or none if the file doesn't exist
+func (f *FileStore) Load(context.Context) ([]Checkpoint, error) {
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var checkpoints []Checkpoint
+	err = json.Unmarshal(b, &checkpoints)
+	return checkpoints, err
+}
+
+// finalSaveTimeout bounds the save made on shutdown, which can't be bounded by the context that's ended
+const finalSaveTimeout = 5 * time.Second
+
+type persistence struct {
+	store               StateStore
+	interval, staleness time.Duration
+}
+
+// loadCheckpoints returns the checkpoints saved no more than the staleness bound ago, keyed by Key, for newBreaker to
+// restore from as breakers are created; if loading fails, an ErrorEvent is published and nothing is restored
+func loadCheckpoints(ctx context.Context, p persistence, d deps) *sync.Map {
+	now := d.clock.Now()
+	checkpoints, err := p.store.Load(ctx)
+	if err != nil {
+		d.publish(ErrorEvent{Published: now, Op: "load checkpoints", Err: err})
+		return nil
+	}
+
+	var restored sync.Map
+	for _, cp := range checkpoints {
+		if now.Sub(cp.Saved) <= p.staleness {
+			restored.Store(cp.Key, cp)
+		}
+	}
+	return &restored
+}
+
+// checkpointEvery saves checkpoints of all breakers at the given interval in the background until ctx is done, and
+// then once more before the breakers stop, so that a clean shutdown loses nothing; failed saves publish an ErrorEvent
+func (bc *cache) checkpointEvery(ctx context.Context, p persistence) {
+	clk := bc.global.clock
+	t := clk.NewTimer(p.interval) // created before returning so that fake clocks see it straight away
+
+	var mu sync.Mutex // so that the final save can't race a periodic one
+	save := func(ctx context.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+		now := clk.Now()
+		if err := p.store.Save(ctx, bc.checkpoints(now)); err != nil {
+			bc.global.deps.publish(ErrorEvent{Published: now, Op: "save checkpoints", Err: err})
+		}
+	}
+
+	bc.beforeClose(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), finalSaveTimeout)
+		defer cancel()
+		save(ctx)
+	})
+
+	go func() {
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C():
+				save(ctx)
+				t.Reset(p.interval)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// checkpoints returns a checkpoint for every running breaker
+func (bc *cache) checkpoints(now time.Time) []Checkpoint {
+	var checkpoints []Checkpoint
+	bc.each(func(b *breaker) {
+		if cp, ok := b.checkpoint(now); ok {
+			checkpoints = append(checkpoints, cp)
+		}
+	})
+	return checkpoints
+}
+
+func (b *breaker) checkpoint(now time.Time) (Checkpoint, bool) {
+	g := b.load()
+	if g == stopped {
+		return Checkpoint{}, false
+	}
+	return Checkpoint{
+		Key:         b.Key,
+		State:       g.GenState,
+		Fails:       int(atomic.LoadInt64(&g.fails)),
+		Passes:      int(atomic.LoadInt64(&g.passes)),
+		Ignored:     int(atomic.LoadInt64(&g.ignored)),
+		FailScore:   float64(atomic.LoadInt64(&g.failScore)) / scoreScale,
+		LastFail:    b.lastFail(),
+		ResetMoment: g.resetMoment,
+		Saved:       now,
+	}, true
+}
+
+// restore resumes the breaker from a checkpoint, scheduling the reset of an Open breaker for its original moment
+func (b *breaker) restore(cp Checkpoint) {
+	from := b.load()
+	to := &generation{
+		GenState:    cp.State,
+		resetMoment: cp.ResetMoment,
+		fails:       int64(cp.Fails),
+		passes:      int64(cp.Passes),
+		ignored:     int64(cp.Ignored),
+		failScore:   int64(cp.FailScore * scoreScale),
+		causes:      b.newCauses(),
+	}
+	if !cp.LastFail.IsZero() {
+		b.lastFailNanos = cp.LastFail.UnixNano()
+	}
+	atomic.StorePointer(&b.gen, unsafe.Pointer(to))
+
+	b.publishState(from, to)
+
+	if to.State() == Open && !to.resetMoment.IsZero() {
+		// open -> half open
+		b.sched.schedule(to.resetMoment, func() { b.transition(to, HalfOpen) })
+	}
+}
@@ -0,0 +1,161 @@
+package grpcbreaker_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
+	"github.com/jwilner/grpcbreaker/pbtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// savingStore signals each save made to the wrapped store
+type savingStore struct {
+	grpcbreaker.StateStore
+	saved chan struct{}
+}
+
+func (s *savingStore) Save(ctx context.Context, checkpoints []grpcbreaker.Checkpoint) error {
+	defer func() {
+		select {
+		case s.saved <- struct{}{}:
+		default:
+		}
+	}()
+	return s.StateStore.Save(ctx, checkpoints)
+}
+
+func TestPersist(t *testing.T) {
+	store := &savingStore{grpcbreaker.NewFileStore(filepath.Join(t.TempDir(), "state.json")), make(chan struct{}, 1)}
+	global := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Fail(codes.Unavailable))
+	ctx := context.Background()
+
+	newBreaker := func(t *testing.T, store grpcbreaker.StateStore, staleness time.Duration) (*grpcbreakertest.Harness, pbtest.SvcAClient) {
+		h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(
+			grpcbreaker.FailThreshold(1),
+			grpcbreaker.ResetTimeout(100*time.Second),
+			grpcbreaker.Persist(store, time.Second, staleness),
+		))
+		return h, pbtest.NewSvcAClient(backend.Dial(t, grpc.WithUnaryInterceptor(h.Breaker.UnaryInterceptor)))
+	}
+
+	// trip the breaker and let it checkpoint
+	h, client := newBreaker(t, store, time.Minute)
+	_, _ = client.Get(ctx, &pbtest.GetRequest{})
+	h.ExpectTransitions(global, grpcbreaker.Closed, grpcbreaker.Open)
+	h.Clock.Advance(time.Second)
+	<-store.saved
+
+	t.Run("restores", func(t *testing.T) {
+		h, client := newBreaker(t, store, time.Minute)
+		h.ExpectTransitions(global, grpcbreaker.Closed, grpcbreaker.Open)
+
+		// the original reset moment is kept
+		h.Clock.Advance(100*time.Second - 1)
+		if _, err := client.Get(ctx, &pbtest.GetRequest{}); !errors.Is(err, grpcbreaker.ErrBreakerOpen) {
+			t.Fatalf("Expected %v but got %v", grpcbreaker.ErrBreakerOpen, err)
+		}
+		h.Clock.Advance(1)
+		h.ExpectTransitions(global, grpcbreaker.Open, grpcbreaker.HalfOpen)
+	})
+
+	t.Run("ignores stale", func(t *testing.T) {
+		stale := grpcbreaker.Checkpoint{
+			Key:   global,
+			State: grpcbreaker.GenState(grpcbreaker.Closed).Next(grpcbreaker.Open),
+			Saved: time.Unix(0, 0).Add(-2 * time.Minute),
+		}
+		// in a store of its own, as breakers save on shutdown
+		store := grpcbreaker.NewFileStore(filepath.Join(t.TempDir(), "stale.json"))
+		if err := store.Save(ctx, []grpcbreaker.Checkpoint{stale}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, client := newBreaker(t, store, time.Minute)
+		if _, err := client.Get(ctx, &pbtest.GetRequest{}); status.Code(err) != codes.Unavailable {
+			t.Fatalf("Expected %v but got %v", codes.Unavailable, err)
+		}
+	})
+}
+
+// failingStore fails every load and save
+type failingStore struct{ err error }
+
+func (s failingStore) Save(context.Context, []grpcbreaker.Checkpoint) error   { return s.err }
+func (s failingStore) Load(context.Context) ([]grpcbreaker.Checkpoint, error) { return nil, s.err }
+
+func TestPersist_savesOnShutdown(t *testing.T) {
+	store := &savingStore{grpcbreaker.NewFileStore(filepath.Join(t.TempDir(), "state.json")), make(chan struct{}, 1)}
+	global := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	br, err := grpcbreaker.New(ctx, grpcbreaker.Global(
+		grpcbreaker.Persist(store, time.Hour, time.Hour),
+		grpcbreaker.WithClock(grpcbreakertest.NewFakeClock(time.Unix(0, 0))),
+		grpcbreaker.FailThreshold(1),
+		grpcbreaker.ResetTimeout(time.Minute),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errDown := errors.New("down")
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return errDown
+	}
+	if err := br.UnaryInterceptor(ctx, "/a.Svc/Get", nil, nil, nil, invoker); err != errDown {
+		t.Fatalf("expected %v but got %v", errDown, err)
+	}
+
+	cancel() // long before the first interval
+	<-store.saved
+
+	checkpoints, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 1 || checkpoints[0].Key != global || checkpoints[0].State.State() != grpcbreaker.Open {
+		t.Fatalf("expected the Open global breaker to be saved but got %+v", checkpoints)
+	}
+}
+
+func TestPersist_errors(t *testing.T) {
+	errStore := errors.New("store unavailable")
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(grpcbreaker.Persist(failingStore{errStore}, time.Second, time.Minute)))
+
+	// waits for the ErrorEvent of the operation
+	expectError := func(op string) {
+		t.Helper()
+		for deadline := time.Now().Add(grpcbreakertest.DefaultTimeout); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			for _, ev := range h.Events() {
+				if e, ok := ev.(grpcbreaker.ErrorEvent); ok && e.Op == op {
+					if !errors.Is(e.Err, errStore) {
+						t.Fatalf("expected %v but got %v", errStore, e.Err)
+					}
+					return
+				}
+			}
+		}
+		t.Fatalf("expected an ErrorEvent for %q in %+v", op, h.Events())
+	}
+
+	expectError("load checkpoints")
+	h.Clock.Advance(time.Second)
+	expectError("save checkpoints")
+}
+
+func TestPersist_invalidInterval(t *testing.T) {
+	store := grpcbreaker.NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := grpcbreaker.New(context.Background(), grpcbreaker.Global(grpcbreaker.Persist(store, interval, time.Minute))); err == nil {
+			t.Fatalf("expected an error for an interval of %v", interval)
+		}
+	}
+}
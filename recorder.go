@@ -0,0 +1,209 @@
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jwilner/grpcbreaker/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Recorder provides interceptors which record one trace.Record per call, e.g. to feed the policy simulator. They
+// should be chained before the Breaker's interceptors so that they observe shed calls. As the breakers don't protect
+// streams, records of streams name no breaker.
+type Recorder struct {
+	UnaryInterceptor  grpc.UnaryClientInterceptor
+	StreamInterceptor grpc.StreamClientInterceptor
+
+	settings recorderSettings
+	cache    *cache
+
+	mu  sync.Mutex
+	w   *trace.Writer
+	err error
+}
+
+type recorderSettings struct {
+	sampleRate float64
+	rand       *rand.Rand
+}
+
+type RecorderOption func(*recorderSettings)
+
+// SampleRate sets the fraction of calls which are recorded, from 0 to 1; the default is to record all calls
+func SampleRate(rate float64) RecorderOption {
+	return func(s *recorderSettings) {
+		s.sampleRate = rate
+	}
+}
+
+// SampleSource sets the source of randomness used for sampling
+func SampleSource(src rand.Source) RecorderOption {
+	return func(s *recorderSettings) {
+		s.rand = rand.New(src)
+	}
+}
+
+// Recorder returns a Recorder writing records of the calls made through this Breaker to w as JSONL; w may be a
+// trace.RotatingWriter
+func (b *Breaker) Recorder(w io.Writer, opts ...RecorderOption) *Recorder {
+	r := &Recorder{
+		settings: recorderSettings{sampleRate: 1, rand: rand.New(rand.NewSource(time.Now().UnixNano()))},
+		cache:    b.cache,
+		w:        trace.NewWriter(w),
+	}
+	for _, o := range opts {
+		o(&r.settings)
+	}
+
+	r.UnaryInterceptor = func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if !r.sample() {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		rec := r.begin(ctx, method, opts)
+		r.observeBreaker(ctx, rec, method, req, opts)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.end(rec, err)
+		return err
+	}
+
+	r.StreamInterceptor = func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if !r.sample() {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		rec := r.begin(ctx, method, opts) // streams aren't protected by the breakers, so there's no breaker to record
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			r.end(rec, err)
+			return nil, err
+		}
+		return newRecordedStream(ctx, cs, desc, func(err error) { r.end(rec, err) }), nil
+	}
+
+	return r
+}
+
+// Err returns the first error encountered writing records, after which no more are written
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) sample() bool {
+	if r.settings.sampleRate >= 1 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.settings.rand.Float64() < r.settings.sampleRate
+}
+
+// begin records what's known of a call before it's made
+func (r *Recorder) begin(ctx context.Context, method string, opts []grpc.CallOption) *trace.Record {
+	rec := &trace.Record{Time: r.cache.global.clock.Now(), Method: method}
+	for _, o := range opts {
+		if c, ok := o.(*CallOption); ok {
+			rec.CallSite = c.optionSet.key.Name
+		}
+	}
+	return rec
+}
+
+// observeBreaker records which breaker will handle a unary call and that breaker's state, without creating or
+// touching its partition; a partition which doesn't exist yet will be created closed
+func (r *Recorder) observeBreaker(
+	ctx context.Context,
+	rec *trace.Record,
+	method string,
+	req interface{},
+	opts []grpc.CallOption,
+) {
+	b := r.cache.resolve(method, opts)
+	state := b.load().State()
+	partition := ""
+	if b.partitions != nil {
+		if partition = b.keyFunc(ctx, method, req); partition != "" {
+			state = Closed
+			if p, ok := b.partitions.lookup(partition); ok {
+				state = p.load().State()
+			}
+		}
+	}
+
+	rec.Breaker = &trace.Breaker{Type: b.Type.String(), Name: b.Name, Partition: partition}
+	rec.State = state.String()
+}
+
+func (r *Recorder) end(rec *trace.Record, err error) {
+	rec.Latency = r.cache.global.clock.Now().Sub(rec.Time)
+	rec.Code = status.Code(err)
+	rec.Shed = errors.Is(err, ErrBreakerOpen)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.w.Write(*rec)
+	}
+}
+
+// recordedStream calls end once, when the stream finishes: when RecvMsg returns an error or io.EOF, when it returns the
+// only response of a stream without server streaming, or when the caller abandons the stream by ending ctx
+type recordedStream struct {
+	grpc.ClientStream
+	desc *grpc.StreamDesc
+	end  func(error)
+	once sync.Once
+}
+
+func newRecordedStream(
+	ctx context.Context,
+	cs grpc.ClientStream,
+	desc *grpc.StreamDesc,
+	end func(error),
+) *recordedStream {
+	s := &recordedStream{ClientStream: cs, desc: desc, end: end}
+	go func() {
+		<-cs.Context().Done() // which is also done once the stream finishes, so this doesn't outlive it
+		if err := ctx.Err(); err != nil {
+			s.finish(status.FromContextError(err).Err())
+		}
+	}()
+	return s
+}
+
+func (s *recordedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF): // a clean finish
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+	case !s.desc.ServerStreams: // e.g. by CloseAndRecv, after which there's nothing more to receive
+		s.finish(nil)
+	}
+	return err
+}
+
+func (s *recordedStream) finish(err error) {
+	s.once.Do(func() { s.end(err) })
+}
@@ -0,0 +1,217 @@
+// Package redisstate implements grpcbreaker.SharedState on a Redis server, or anything speaking its protocol. Each
+// breaker's peer reports are kept as JSON in a hash keyed by peer, which expires if no peer reports for a while.
+package redisstate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+// State is a grpcbreaker.SharedState backed by a Redis server
+type State struct {
+	addr    string
+	prefix  string
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu   sync.Mutex // serializes commands on the connection
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+var _ grpcbreaker.SharedState = (*State)(nil)
+
+// Option configures a State
+type Option func(*State)
+
+// Prefix sets the prefix of the keys used for each breaker; the default is "grpcbreaker:"
+func Prefix(prefix string) Option {
+	return func(s *State) {
+		s.prefix = prefix
+	}
+}
+
+// TTL sets how long a breaker's hash outlives its last report; the default is a minute
+func TTL(ttl time.Duration) Option {
+	return func(s *State) {
+		s.ttl = ttl
+	}
+}
+
+// Timeout bounds each command, including connecting, unless the context has an earlier deadline; the default is five
+// seconds, and zero means no bound
+func Timeout(timeout time.Duration) Option {
+	return func(s *State) {
+		s.timeout = timeout
+	}
+}
+
+// New returns a State for the Redis server at addr; connections are made lazily and remade after errors
+func New(addr string, opts ...Option) *State {
+	s := &State{addr: addr, prefix: "grpcbreaker:", ttl: time.Minute, timeout: 5 * time.Second}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Publish records the peer's state
+func (s *State) Publish(ctx context.Context, state grpcbreaker.PeerState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	key := s.key(state.Key)
+	if _, err := s.do(ctx, "HSET", key, state.Peer, string(b)); err != nil {
+		return err
+	}
+	_, err = s.do(ctx, "PEXPIRE", key, strconv.FormatInt(s.ttl.Milliseconds(), 10))
+	return err
+}
+
+// Peers returns every peer's latest state for the key
+func (s *State) Peers(ctx context.Context, key grpcbreaker.Key) ([]grpcbreaker.PeerState, error) {
+	reply, err := s.do(ctx, "HGETALL", s.key(key))
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := reply.([]interface{})
+	if !ok || len(fields)%2 != 0 {
+		return nil, fmt.Errorf("redisstate: unexpected reply to HGETALL: %v", reply)
+	}
+
+	states := make([]grpcbreaker.PeerState, 0, len(fields)/2)
+	for i := 1; i < len(fields); i += 2 {
+		v, _ := fields[i].(string)
+		var st grpcbreaker.PeerState
+		if err := json.Unmarshal([]byte(v), &st); err != nil {
+			return nil, fmt.Errorf("redisstate: invalid state of peer %v: %w", fields[i-1], err)
+		}
+		states = append(states, st)
+	}
+	return states, nil
+}
+
+// Close closes any open connection
+func (s *State) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.rd = nil, nil
+	return err
+}
+
+func (s *State) key(k grpcbreaker.Key) string {
+	return s.prefix + k.String()
+}
+
+// Error is an error reply from the server
+type Error string
+
+func (e Error) Error() string {
+	return "redisstate: " + string(e)
+}
+
+// do sends a command and reads its reply, which is a string, an int64, a []interface{} of replies, or nil
+func (s *State) do(ctx context.Context, args ...string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timeout > 0 {
+		var cncl context.CancelFunc
+		ctx, cncl = context.WithTimeout(ctx, s.timeout)
+		defer cncl()
+	}
+
+	if s.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", s.addr)
+		if err != nil {
+			return nil, err
+		}
+		s.conn, s.rd = conn, bufio.NewReader(conn)
+	}
+
+	deadline, _ := ctx.Deadline() // the zero value means none
+	_ = s.conn.SetDeadline(deadline)
+
+	reply, err := s.roundTrip(args)
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) {
+		// the connection is in an unknown state
+		_ = s.conn.Close()
+		s.conn, s.rd = nil, nil
+	}
+	return reply, err
+}
+
+func (s *State) roundTrip(args []string) (interface{}, error) {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, a := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(a))+"\r\n"+a+"\r\n"...)
+	}
+	if _, err := s.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(s.rd)
+}
+
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redisstate: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, Error(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err // a nil bulk string if n is -1
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		return string(b[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		replies := make([]interface{}, n)
+		for i := range replies {
+			if replies[i], err = readReply(rd); err != nil {
+				return nil, err
+			}
+		}
+		return replies, nil
+	default:
+		return nil, fmt.Errorf("redisstate: malformed reply %q", line)
+	}
+}
@@ -0,0 +1,242 @@
+package redisstate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+)
+
+func TestState(t *testing.T) {
+	srv := newStandIn(t)
+	s := New(srv.addr(), TTL(time.Second))
+	t.Cleanup(func() { _ = s.Close() })
+
+	ctx := context.Background()
+	key := grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/foo.Svc"}
+	opened := grpcbreaker.GenState(grpcbreaker.Closed).Next(grpcbreaker.Open)
+	reported := time.Unix(10, 0).UTC()
+
+	for _, st := range []grpcbreaker.PeerState{
+		{Peer: "a", Key: key, State: opened, Fails: 3, FailScore: 3, Reported: reported},
+		{Peer: "b", Key: key, State: grpcbreaker.GenState(grpcbreaker.Closed), Reported: reported},
+		{Peer: "a", Key: grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}, Reported: reported},
+	} {
+		if err := s.Publish(ctx, st); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	peers, err := s.Peers(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Peer < peers[j].Peer })
+	want := []grpcbreaker.PeerState{
+		{Peer: "a", Key: key, State: opened, Fails: 3, FailScore: 3, Reported: reported},
+		{Peer: "b", Key: key, State: grpcbreaker.GenState(grpcbreaker.Closed), Reported: reported},
+	}
+	if !reflect.DeepEqual(peers, want) {
+		t.Fatalf("Expected %+v but got %+v", want, peers)
+	}
+
+	if ttl := srv.ttl("grpcbreaker:" + key.String()); ttl != "1000" {
+		t.Fatalf("Expected a TTL of 1000ms but got %q", ttl)
+	}
+
+	t.Run("reconnects", func(t *testing.T) {
+		srv.dropConns()
+		if _, err := s.Peers(ctx, key); err == nil {
+			t.Fatal("Expected an error from the dropped connection")
+		}
+		if peers, err := s.Peers(ctx, key); err != nil || len(peers) != 2 {
+			t.Fatalf("Expected 2 peers after reconnecting but got %v, %v", peers, err)
+		}
+	})
+
+	t.Run("error replies", func(t *testing.T) {
+		srv.fail("READONLY You can't write against a read only replica.")
+		if err := s.Publish(ctx, want[0]); err != Error("READONLY You can't write against a read only replica.") {
+			t.Fatalf("Expected the error reply but got %v", err)
+		}
+	})
+}
+
+func TestState_timeout(t *testing.T) {
+	// a server which accepts connections but never replies
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	t.Cleanup(func() {
+		_ = ln.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+
+	s := New(ln.Addr().String(), Timeout(50*time.Millisecond))
+	defer func() { _ = s.Close() }()
+
+	done := make(chan error, 1)
+	go func() { done <- s.Publish(context.Background(), grpcbreaker.PeerState{Peer: "a"}) }()
+	select {
+	case err := <-done:
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Fatalf("expected a timeout but got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Publish to time out")
+	}
+}
+
+// standIn is a tiny in-process server speaking just enough of the Redis protocol for State
+type standIn struct {
+	ln net.Listener
+
+	mu      sync.Mutex
+	hashes  map[string]map[string]string
+	ttls    map[string]string
+	conns   []net.Conn
+	failure string // if set, returned as the error reply to every command
+}
+
+func newStandIn(t *testing.T) *standIn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &standIn{ln: ln, hashes: make(map[string]map[string]string), ttls: make(map[string]string)}
+	t.Cleanup(func() {
+		_ = ln.Close()
+		s.dropConns()
+	})
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *standIn) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *standIn) ttl(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ttls[key]
+}
+
+func (s *standIn) fail(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failure = msg
+}
+
+func (s *standIn) dropConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+	s.conns = nil
+}
+
+func (s *standIn) serve(conn net.Conn) {
+	rd := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(rd)
+		if err != nil {
+			_ = conn.Close()
+			return
+		}
+		if _, err := io.WriteString(conn, s.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *standIn) exec(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failure != "" {
+		return "-" + s.failure + "\r\n"
+	}
+
+	switch {
+	case args[0] == "HSET" && len(args) == 4:
+		h, ok := s.hashes[args[1]]
+		if !ok {
+			h = make(map[string]string)
+			s.hashes[args[1]] = h
+		}
+		h[args[2]] = args[3]
+		return ":1\r\n"
+	case args[0] == "PEXPIRE" && len(args) == 3:
+		s.ttls[args[1]] = args[2]
+		return ":1\r\n"
+	case args[0] == "HGETALL" && len(args) == 2:
+		h := s.hashes[args[1]]
+		reply := fmt.Sprintf("*%d\r\n", 2*len(h))
+		for k, v := range h {
+			reply += fmt.Sprintf("$%d\r\n%s\r\n$%d\r\n%s\r\n", len(k), k, len(v), v)
+		}
+		return reply
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	reply, err := readReply(rd)
+	if err != nil {
+		return nil, err
+	}
+	elems, ok := reply.([]interface{})
+	if !ok || len(elems) == 0 {
+		return nil, fmt.Errorf("not a command: %v", reply)
+	}
+	args := make([]string, len(elems))
+	for i, e := range elems {
+		args[i] = e.(string)
+	}
+	return args, nil
+}
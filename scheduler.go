@@ -0,0 +1,161 @@
+package grpcbreaker
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	wheelTick  = time.Millisecond // the resolution of the scheduler; tasks run at the first tick at or after their moment
+	wheelSlots = 1024             // so a revolution of the wheel is about a second
+)
+
+// scheduler runs functions at given moments on a hashed timing wheel shared by every breaker, rather than each breaker
+// needing its own timer and goroutine. Scheduling a task is O(1) however many are pending: it's appended to the slot
+// of its tick. A single timer is armed for the next tick whose slot holds any task, so the wheel doesn't tick while
+// idle; tasks more than a revolution away stay in their slot until the wheel comes round to their tick.
+type scheduler struct {
+	clock Clock
+
+	mu      sync.Mutex
+	slots   [wheelSlots][]task
+	pending int   // the number of tasks across all slots
+	last    int64 // the last tick whose tasks have been run, to which already due tasks are added
+	timer   Timer
+	next    int64 // the tick the timer is armed for, if armed
+	armed   bool
+	stopped bool
+}
+
+func newScheduler(clock Clock) *scheduler {
+	return &scheduler{clock: clock, last: floorTick(clock.Now())}
+}
+
+type task struct {
+	tick int64
+	f    func()
+}
+
+// schedule arranges for f to be run at or shortly after the given moment
+func (s *scheduler) schedule(at time.Time, f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return
+	}
+
+	tick := ceilTick(at)
+	if !at.After(s.clock.Now()) || tick < s.last {
+		tick = s.last // already due, so run straight away rather than a revolution from now
+	}
+	slot := &s.slots[slotOf(tick)]
+	*slot = append(*slot, task{tick, f})
+	s.pending++
+
+	if !s.armed || tick < s.next {
+		s.arm(tick)
+	}
+}
+
+// arm sets the timer for the given tick; s.mu must be held
+func (s *scheduler) arm(tick int64) {
+	s.next, s.armed = tick, true
+	d := time.Unix(0, tick*int64(wheelTick)).Sub(s.clock.Now())
+	if s.timer == nil {
+		s.timer = s.clock.AfterFunc(d, s.fire)
+		return
+	}
+	s.timer.Reset(d)
+}
+
+func (s *scheduler) fire() {
+	now := floorTick(s.clock.Now())
+
+	var due []func()
+	s.mu.Lock()
+	s.armed = false
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+
+	// run the slots of every tick from the last, which may have had tasks added since, visiting each slot at most once
+	// however long it's been
+	end := now
+	if end >= s.last+wheelSlots {
+		end = s.last + wheelSlots - 1
+	}
+	for tick := s.last; tick <= end; tick++ {
+		slot := &s.slots[slotOf(tick)]
+		kept := (*slot)[:0]
+		for _, t := range *slot {
+			if t.tick <= now {
+				due = append(due, t.f)
+			} else {
+				kept = append(kept, t)
+			}
+		}
+		for i := len(kept); i < len(*slot); i++ {
+			(*slot)[i] = task{} // don't retain the func
+		}
+		*slot = kept
+	}
+	if now > s.last {
+		s.last = now
+	}
+	s.pending -= len(due)
+
+	// rearm for the next tick with anything in its slot, which is at most a revolution away
+	for tick := s.last + 1; s.pending > 0 && tick <= s.last+wheelSlots; tick++ {
+		if len(s.slots[slotOf(tick)]) > 0 {
+			s.arm(tick)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	for _, f := range due {
+		f()
+	}
+}
+
+// stop discards all pending tasks and prevents any more from being scheduled
+func (s *scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	s.slots = [wheelSlots][]task{}
+	s.pending = 0
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// floorTick returns the tick in progress at t
+func floorTick(t time.Time) int64 {
+	n := t.UnixNano()
+	tick := n / int64(wheelTick)
+	if n%int64(wheelTick) < 0 {
+		tick--
+	}
+	return tick
+}
+
+// ceilTick returns the first tick beginning at or after t
+func ceilTick(t time.Time) int64 {
+	tick := floorTick(t)
+	if t.UnixNano() != tick*int64(wheelTick) {
+		tick++
+	}
+	return tick
+}
+
+func slotOf(tick int64) int {
+	slot := tick % wheelSlots
+	if slot < 0 {
+		slot += wheelSlots
+	}
+	return int(slot)
+}
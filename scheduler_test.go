@@ -0,0 +1,63 @@
+package grpcbreaker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker/internal/clock"
+)
+
+func Test_scheduler(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	s := newScheduler(clk)
+
+	var ran []string
+	at := func(d time.Duration, name string) {
+		s.schedule(time.Unix(0, 0).Add(d), func() { ran = append(ran, name) })
+	}
+	at(time.Hour, "beyond many revolutions")
+	at(time.Second+time.Microsecond, "within a tick")
+	at(time.Second, "on a tick")
+	at(time.Second, "on the same tick")
+	at(-time.Second, "in the past")
+
+	clk.Advance(0)
+	if exp := []string{"in the past"}; !reflect.DeepEqual(ran, exp) {
+		t.Fatalf("expected %v but got %v", exp, ran)
+	}
+
+	clk.Advance(time.Second - 1)
+	if len(ran) != 1 {
+		t.Fatalf("expected nothing to run early but got %v", ran)
+	}
+	clk.Advance(1)
+	if exp := []string{"in the past", "on a tick", "on the same tick"}; !reflect.DeepEqual(ran, exp) {
+		t.Fatalf("expected %v but got %v", exp, ran)
+	}
+	clk.Advance(time.Millisecond)
+	if exp := "within a tick"; ran[len(ran)-1] != exp {
+		t.Fatalf("expected %q to run at the next tick but got %v", exp, ran)
+	}
+
+	clk.Set(time.Unix(0, 0).Add(time.Hour - time.Millisecond))
+	if len(ran) != 4 {
+		t.Fatalf("expected nothing to run early but got %v", ran)
+	}
+	clk.Advance(time.Millisecond)
+	if exp := "beyond many revolutions"; len(ran) != 5 || ran[4] != exp {
+		t.Fatalf("expected %q to run but got %v", exp, ran)
+	}
+	if s.pending != 0 {
+		t.Fatalf("expected no pending tasks but got %d", s.pending)
+	}
+
+	// stopping discards what's pending and ignores what's scheduled after
+	at(2*time.Hour, "discarded")
+	s.stop()
+	at(2*time.Hour, "ignored")
+	clk.Advance(2 * time.Hour)
+	if len(ran) != 5 {
+		t.Fatalf("expected nothing to run after stopping but got %v", ran)
+	}
+}
@@ -0,0 +1,205 @@
+package grpcbreaker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PeerState is one replica's report of the state of a breaker
+type PeerState struct {
+	Peer      string
+	Key       Key
+	State     GenState
+	Fails     int
+	FailScore float64
+	Reported  time.Time
+	// ByPeers is set when the breaker is Open only because of other replicas' reports, which therefore don't count it
+	// toward their quorums; otherwise replicas would keep each other open after the dependency recovers
+	ByPeers bool
+}
+
+// SharedState is a backend through which replicas share the state of their breakers, so that each can learn that a
+// dependency is failing from its peers rather than only from its own calls
+type SharedState interface {
+	// Publish records the latest state of one of this replica's breakers
+	Publish(ctx context.Context, state PeerState) error
+	// Peers returns the latest state reported by each replica, this one included, for the breaker with the given key
+	Peers(ctx context.Context, key Key) ([]PeerState, error)
+}
+
+// MemoryState is a SharedState for replicas within a single process, mostly useful for testing
+type MemoryState struct {
+	mu sync.Mutex
+	m  map[Key]map[string]PeerState
+}
+
+// NewMemoryState returns an empty MemoryState
+func NewMemoryState() *MemoryState {
+	return &MemoryState{m: make(map[Key]map[string]PeerState)}
+}
+
+// Publish records the peer's state
+func (s *MemoryState) Publish(_ context.Context, state PeerState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	peers, ok := s.m[state.Key]
+	if !ok {
+		peers = make(map[string]PeerState)
+		s.m[state.Key] = peers
+	}
+	peers[state.Peer] = state
+	return nil
+}
+
+// Peers returns every peer's latest state for the key
+func (s *MemoryState) Peers(_ context.Context, key Key) ([]PeerState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]PeerState, 0, len(s.m[key]))
+	for _, st := range s.m[key] {
+		states = append(states, st)
+	}
+	return states, nil
+}
+
+type sharing struct {
+	state    SharedState
+	peer     string
+	interval time.Duration
+}
+
+const (
+	// staleIntervals is how many sharing intervals may pass before a peer's report is ignored
+	staleIntervals = 3
+	// shareConcurrency bounds how many breakers' states are shared at once
+	shareConcurrency = 16
+)
+
+type quorum struct {
+	min      int
+	fraction float64
+}
+
+// reached reports whether enough fresh peers, not counting self, report Open
+func (q quorum) reached(self string, peers []PeerState, freshAfter time.Time) bool {
+	if q.min <= 0 && q.fraction <= 0 {
+		return false
+	}
+
+	var fresh, open int
+	for _, p := range peers {
+		if p.Peer == self || p.Reported.Before(freshAfter) {
+			continue
+		}
+		fresh++
+		if p.State.State() == Open && !p.ByPeers {
+			open++
+		}
+	}
+	return open > 0 && open >= q.min && float64(open) >= q.fraction*float64(fresh)
+}
+
+// shareEvery publishes the state of all breakers and applies peers' states at the given interval in the background
+// until ctx is done; failures publish an ErrorEvent
+func (bc *cache) shareEvery(ctx context.Context, s sharing) {
+	clk := bc.global.clock
+	t := clk.NewTimer(s.interval) // created before returning so that fake clocks see it straight away
+
+	go func() {
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C():
+				now := clk.Now()
+				if err := bc.share(ctx, s, now); err != nil {
+					bc.global.deps.publish(ErrorEvent{Published: now, Op: "share state", Err: err})
+				}
+				t.Reset(s.interval)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// share publishes the state of every breaker and opens any Closed breaker whose peers have reached its quorum. The
+// breakers are shared concurrently, and within the interval, so that a slow backend can't hold up the next; it gives
+// up on the first error, as the backend is then likely unavailable.
+func (bc *cache) share(ctx context.Context, s sharing, now time.Time) error {
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, shareConcurrency)
+		failOnce sync.Once
+		err      error
+	)
+	bc.each(func(b *breaker) {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if e := bc.shareBreaker(ctx, s, b, now); e != nil {
+				failOnce.Do(func() {
+					err = e
+					cancel()
+				})
+			}
+		}()
+	})
+	wg.Wait()
+
+	if err == nil && parent.Err() == nil {
+		err = ctx.Err() // the interval passed before every breaker was shared
+	}
+	return err
+}
+
+// shareBreaker publishes the state of the breaker, and opens it if it's Closed and its peers have reached its quorum
+func (bc *cache) shareBreaker(ctx context.Context, s sharing, b *breaker, now time.Time) error {
+	g := b.load()
+	if g == stopped {
+		return nil
+	}
+
+	if err := s.state.Publish(ctx, PeerState{
+		Peer:      s.peer,
+		Key:       b.Key,
+		State:     g.GenState,
+		Fails:     int(atomic.LoadInt64(&g.fails)),
+		FailScore: float64(atomic.LoadInt64(&g.failScore)) / scoreScale,
+		Reported:  now,
+		ByPeers:   g.byPeers,
+	}); err != nil {
+		return err
+	}
+
+	if g.State() != Closed || (b.quorum == quorum{}) {
+		return nil
+	}
+
+	peers, err := s.state.Peers(ctx, b.Key)
+	if err != nil {
+		return err
+	}
+	if b.quorum.reached(s.peer, peers, now.Add(-staleIntervals*s.interval)) {
+		// closed -> open
+		to := b.next(g, Open, b.reset)
+		to.byPeers = true
+		b.swap(g, to)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package grpcbreaker_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
+	"github.com/jwilner/grpcbreaker/pbtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// signalingState signals each publication and lookup made in the wrapped shared state
+type signalingState struct {
+	grpcbreaker.SharedState
+	published chan string
+	looked    chan struct{}
+}
+
+func (s *signalingState) Publish(ctx context.Context, state grpcbreaker.PeerState) error {
+	defer func() { s.published <- state.Peer }()
+	return s.SharedState.Publish(ctx, state)
+}
+
+func (s *signalingState) Peers(ctx context.Context, key grpcbreaker.Key) ([]grpcbreaker.PeerState, error) {
+	defer func() { s.looked <- struct{}{} }()
+	return s.SharedState.Peers(ctx, key)
+}
+
+func TestShare(t *testing.T) {
+	state := &signalingState{grpcbreaker.NewMemoryState(), make(chan string, 10), make(chan struct{}, 10)}
+	global := grpcbreaker.Key{Type: grpcbreaker.BreakerGlobal}
+	backend := grpcbreakertest.NewBackend(t, grpcbreakertest.Fail(codes.Unavailable))
+
+	replica := func(peer string, opts ...grpcbreaker.Option) *grpcbreakertest.Harness {
+		return grpcbreakertest.NewBreaker(t, grpcbreaker.Global(append([]grpcbreaker.Option{
+			grpcbreaker.FailThreshold(1),
+			grpcbreaker.ResetTimeout(time.Minute),
+			grpcbreaker.Share(state, peer, time.Second),
+		}, opts...)...))
+	}
+
+	a := replica("a")
+	b := replica("b", grpcbreaker.PeerQuorum(1, 0.5))
+	c := replica("c", grpcbreaker.PeerQuorum(2, 0))
+
+	// a trips and shares that it's open
+	client := pbtest.NewSvcAClient(backend.Dial(t, grpc.WithUnaryInterceptor(a.Breaker.UnaryInterceptor)))
+	_, _ = client.Get(context.Background(), &pbtest.GetRequest{})
+	a.ExpectTransitions(global, grpcbreaker.Closed, grpcbreaker.Open)
+	a.Clock.Advance(time.Second)
+	if peer := <-state.published; peer != "a" {
+		t.Fatalf("Expected a to publish but got %v", peer)
+	}
+
+	// b follows a, as one of its two peers is enough
+	b.Clock.Advance(time.Second)
+	<-state.looked
+	b.ExpectTransitions(global, grpcbreaker.Closed, grpcbreaker.Open)
+
+	// but c requires two peers to be open, and b's not yet shared its state
+	c.Clock.Advance(time.Second)
+	<-state.looked
+	expectNoTransitions := func() {
+		t.Helper()
+		for _, ev := range c.Events() {
+			if e, ok := ev.(grpcbreaker.StateEvent); ok && e.Transition() {
+				t.Fatalf("Expected no transitions but got %v->%v", e.Old, e.New)
+			}
+		}
+	}
+	expectNoTransitions()
+
+	// nor does b count once it has shared, as it only opened because of a
+	b.Clock.Advance(time.Second)
+	reportOf := func(peer string) grpcbreaker.PeerState {
+		peers, err := state.SharedState.Peers(context.Background(), global)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range peers {
+			if p.Peer == peer {
+				return p
+			}
+		}
+		return grpcbreaker.PeerState{}
+	}
+	for peer := range state.published { // skipping b's first report
+		if peer == "b" && reportOf("b").Reported.Equal(b.Clock.Now()) {
+			break
+		}
+	}
+	if p := reportOf("b"); p.State.State() != grpcbreaker.Open || !p.ByPeers {
+		t.Fatalf("Expected b to report being opened by its peers but got %+v", p)
+	}
+	c.Clock.Advance(time.Second)
+	<-state.looked
+	expectNoTransitions()
+}
+
+// failingState fails every publication
+type failingState struct {
+	grpcbreaker.SharedState
+	err error
+}
+
+func (s failingState) Publish(context.Context, grpcbreaker.PeerState) error { return s.err }
+
+func TestShare_errors(t *testing.T) {
+	errState := errors.New("state unavailable")
+	h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(
+		grpcbreaker.Share(failingState{grpcbreaker.NewMemoryState(), errState}, "a", time.Second),
+	))
+	h.Clock.Advance(time.Second)
+
+	for deadline := time.Now().Add(grpcbreakertest.DefaultTimeout); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		for _, ev := range h.Events() {
+			if e, ok := ev.(grpcbreaker.ErrorEvent); ok && e.Op == "share state" {
+				if !errors.Is(e.Err, errState) {
+					t.Fatalf("expected %v but got %v", errState, e.Err)
+				}
+				return
+			}
+		}
+	}
+	t.Fatalf("expected an ErrorEvent in %+v", h.Events())
+}
+
+func TestShare_invalidInterval(t *testing.T) {
+	share := grpcbreaker.Share(grpcbreaker.NewMemoryState(), "a", 0)
+	if _, err := grpcbreaker.New(context.Background(), grpcbreaker.Global(share)); err == nil {
+		t.Fatal("expected an error for an interval of 0")
+	}
+}
+
+// barrierState blocks each publication until n are in flight at once, or until ctx is done
+type barrierState struct {
+	grpcbreaker.SharedState
+	n       int
+	mu      sync.Mutex
+	waiting int
+	all     chan struct{}
+}
+
+func (s *barrierState) Publish(ctx context.Context, state grpcbreaker.PeerState) error {
+	s.mu.Lock()
+	if s.waiting++; s.waiting == s.n {
+		close(s.all)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.all:
+		return s.SharedState.Publish(ctx, state)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestShare_concurrent(t *testing.T) {
+	sets := []*grpcbreaker.OptionSet{
+		grpcbreaker.Service("/a.Svc"),
+		grpcbreaker.Service("/b.Svc"),
+		grpcbreaker.Service("/c.Svc"),
+	}
+	last := grpcbreaker.Key{Type: grpcbreaker.BreakerService, Name: "/c.Svc"}
+
+	t.Run("publishes at once", func(t *testing.T) {
+		state := &barrierState{SharedState: grpcbreaker.NewMemoryState(), n: 4, all: make(chan struct{})}
+		h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(grpcbreaker.Share(state, "a", time.Minute)), sets...)
+		h.Clock.Advance(time.Minute)
+
+		// everything's published once the global and three services' breakers are in flight together
+		<-state.all
+		deadline := time.Now().Add(grpcbreakertest.DefaultTimeout)
+		for ; time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			if peers, _ := state.Peers(context.Background(), last); len(peers) == 1 {
+				return
+			}
+		}
+		t.Fatal("expected every breaker to be published")
+	})
+
+	t.Run("within the interval", func(t *testing.T) {
+		state := &barrierState{SharedState: grpcbreaker.NewMemoryState(), n: 100, all: make(chan struct{})}
+		interval := 50 * time.Millisecond
+		h := grpcbreakertest.NewBreaker(t, grpcbreaker.Global(grpcbreaker.Share(state, "a", interval)), sets...)
+		h.Clock.Advance(interval)
+
+		// the barrier's never reached, so publishing gives up once the interval has passed
+		deadline := time.Now().Add(grpcbreakertest.DefaultTimeout)
+		for ; time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			for _, ev := range h.Events() {
+				if e, ok := ev.(grpcbreaker.ErrorEvent); ok && errors.Is(e.Err, context.DeadlineExceeded) {
+					return
+				}
+			}
+		}
+		t.Fatalf("expected publishing to time out but got %+v", h.Events())
+	})
+}
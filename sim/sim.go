@@ -0,0 +1,293 @@
+// Package sim replays recorded traffic through grpcbreaker on a virtual clock, reporting how candidate policies would
+// have behaved. Calls are replayed with their recorded start times, latencies and status codes; the breakers are the
+// real ones, so the simulation reflects exactly what grpcbreaker would have done.
+package sim
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/internal/clock"
+	"github.com/jwilner/grpcbreaker/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policy is a named candidate configuration, as would be passed to grpcbreaker.New
+type Policy struct {
+	Name       string
+	Global     *grpcbreaker.GlobalOptionSet
+	OptionSets []*grpcbreaker.OptionSet
+}
+
+// Report summarizes how a Policy would have fared against a trace
+type Report struct {
+	Policy string
+	// Calls is the number of calls in the trace
+	Calls int
+	// Unreplayed is the number of calls in the trace which the recording breaker shed; as they never reached the
+	// backend their outcomes are unknown, so they're not replayed
+	Unreplayed int
+	// Shed is the number of calls the breakers would have rejected
+	Shed int
+	// ShedSuccesses is the number of shed calls which in fact succeeded
+	ShedSuccesses int
+	// Trips is the number of times a breaker went from Closed to Open
+	Trips int
+	// FalseTrips is the number of trips which shed calls, all of which in fact succeeded
+	FalseTrips int
+	// MeanTimeToTrip is the mean time from the first failure seen by a closed breaker to it tripping
+	MeanTimeToTrip time.Duration
+}
+
+// Simulate replays the JSONL trace read from r through each of the policies side by side. Records should be ordered
+// by time; a record earlier than its predecessor is treated as having started at the same time.
+func Simulate(r io.Reader, policies ...Policy) ([]Report, error) {
+	runs := make([]*run, 0, len(policies))
+	defer func() {
+		for _, r := range runs {
+			r.cncl()
+		}
+	}()
+
+	tr := trace.NewReader(r)
+	for {
+		rec, err := tr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(runs) == 0 { // start all the clocks at the time of the first record
+			for _, p := range policies {
+				run, err := newRun(p, rec.Time)
+				if err != nil {
+					return nil, fmt.Errorf("policy %v: %w", p.Name, err)
+				}
+				runs = append(runs, run)
+			}
+		}
+
+		for _, r := range runs {
+			r.step(rec)
+		}
+	}
+
+	reports := make([]Report, 0, len(policies))
+	for i, p := range policies {
+		if i >= len(runs) { // empty trace
+			reports = append(reports, Report{Policy: p.Name})
+			continue
+		}
+		reports = append(reports, runs[i].finish())
+	}
+	return reports, nil
+}
+
+// run is the simulation of a single policy
+type run struct {
+	report Report
+
+	clock *clock.Fake
+	br    *grpcbreaker.Breaker
+	cncl  context.CancelFunc
+
+	inFlight callHeap
+	keys     map[grpcbreaker.Key]*keyState
+	shedCode codes.Code // the code of the call being shed, if any
+
+	timeToTrip time.Duration // summed over all trips
+}
+
+// keyState tracks the trips of a single breaker
+type keyState struct {
+	onset              time.Time // the first failure since the breaker closed, zero if none
+	tripped            bool
+	shed, shedFailures int // during the current trip
+}
+
+// call is a call which has reached the backend and will complete at the given time
+type call struct {
+	done     time.Time
+	release  chan struct{}
+	finished <-chan error
+}
+
+func newRun(p Policy, start time.Time) (*run, error) {
+	ctx, cncl := context.WithCancel(context.Background())
+	clk := clock.NewFake(start)
+	br, err := grpcbreaker.New(ctx, p.Global.With(grpcbreaker.WithClock(clk)), p.OptionSets...)
+	if err != nil {
+		cncl()
+		return nil, err
+	}
+	return &run{
+		report: Report{Policy: p.Name},
+		clock:  clk,
+		br:     br,
+		cncl:   cncl,
+		keys:   make(map[grpcbreaker.Key]*keyState),
+	}, nil
+}
+
+func (r *run) step(rec trace.Record) {
+	r.complete(rec.Time)
+	r.clock.Set(rec.Time)
+	r.drain()
+
+	r.report.Calls++
+	if rec.Shed {
+		r.report.Unreplayed++
+		return
+	}
+
+	var opts []grpc.CallOption
+	if rec.CallSite != "" {
+		opts = append(opts, grpcbreaker.CallSite(rec.CallSite))
+	}
+
+	// the call runs in its own goroutine, blocking in the invoker until the clock reaches its completion time
+	release, invoked, finished := make(chan struct{}), make(chan struct{}), make(chan error, 1)
+	go func() {
+		finished <- r.br.UnaryInterceptor(
+			context.Background(),
+			rec.Method,
+			nil,
+			nil,
+			nil,
+			func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				close(invoked)
+				<-release
+				if rec.Code == codes.OK {
+					return nil
+				}
+				return status.Error(rec.Code, rec.Code.String())
+			},
+			opts...,
+		)
+	}()
+
+	select {
+	case err := <-finished: // never reached the invoker, so must have been shed
+		if errors.Is(err, grpcbreaker.ErrBreakerOpen) {
+			r.report.Shed++
+			if rec.Code == codes.OK {
+				r.report.ShedSuccesses++
+			}
+		}
+		r.shedCode = rec.Code
+		r.drain()
+		r.shedCode = codes.OK
+	case <-invoked:
+		heap.Push(&r.inFlight, &call{rec.Time.Add(rec.Latency), release, finished})
+	}
+}
+
+// complete finishes, in order, all calls in flight which complete by the given time
+func (r *run) complete(until time.Time) {
+	for len(r.inFlight) > 0 && !r.inFlight[0].done.After(until) {
+		c := heap.Pop(&r.inFlight).(*call)
+		r.clock.Set(c.done)
+		r.drain()
+		close(c.release)
+		<-c.finished
+		r.drain()
+	}
+}
+
+// drain observes all the events the breakers have published so far; as breakers publish synchronously with calls
+// and the clock, this accounts for everything which has happened
+func (r *run) drain() {
+	for {
+		select {
+		case ev := <-r.br.Events:
+			r.observe(ev)
+		default:
+			return
+		}
+	}
+}
+
+func (r *run) observe(ev grpcbreaker.Event) {
+	switch e := ev.(type) {
+	case grpcbreaker.StateEvent:
+		ks := r.key(e.Key)
+		switch {
+		case !e.Transition():
+			if e.New.State() == grpcbreaker.Closed && e.Fails > 0 && ks.onset.IsZero() {
+				ks.onset = e.LastFail
+			}
+
+		case e.Old.State() == grpcbreaker.Closed && e.New.State() == grpcbreaker.Open:
+			onset := ks.onset
+			if onset.IsZero() { // tripped on its first failure
+				onset = e.LastFail
+			}
+			r.report.Trips++
+			r.timeToTrip += e.Published.Sub(onset)
+			*ks = keyState{tripped: true}
+
+		case e.New.State() == grpcbreaker.Closed:
+			r.endTrip(ks)
+		}
+
+	case grpcbreaker.ShedEvent:
+		ks := r.key(e.Key)
+		ks.shed++
+		if r.shedCode != codes.OK {
+			ks.shedFailures++
+		}
+	}
+}
+
+func (r *run) key(k grpcbreaker.Key) *keyState {
+	ks, ok := r.keys[k]
+	if !ok {
+		ks = new(keyState)
+		r.keys[k] = ks
+	}
+	return ks
+}
+
+func (r *run) endTrip(ks *keyState) {
+	if ks.tripped && ks.shed > 0 && ks.shedFailures == 0 {
+		r.report.FalseTrips++
+	}
+	*ks = keyState{}
+}
+
+func (r *run) finish() Report {
+	for len(r.inFlight) > 0 {
+		r.complete(r.inFlight[0].done)
+	}
+	for _, ks := range r.keys {
+		r.endTrip(ks)
+	}
+	if r.report.Trips > 0 {
+		r.report.MeanTimeToTrip = r.timeToTrip / time.Duration(r.report.Trips)
+	}
+	return r.report
+}
+
+// callHeap implements heap.Interface, ordering calls by completion time
+type callHeap []*call
+
+func (h callHeap) Len() int            { return len(h) }
+func (h callHeap) Less(i, j int) bool  { return h[i].done.Before(h[j].done) }
+func (h callHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *callHeap) Push(x interface{}) { *h = append(*h, x.(*call)) }
+
+func (h *callHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,100 @@
+package sim
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/trace"
+	"google.golang.org/grpc/codes"
+)
+
+func TestSimulate(t *testing.T) {
+	// a call a second, each taking 100ms; calls 10 through 14 fail
+	var buf bytes.Buffer
+	w := trace.NewWriter(&buf)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 25; i++ {
+		code := codes.OK
+		if 10 <= i && i < 15 {
+			code = codes.Unavailable
+		}
+		rec := trace.Record{
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Method:  "/pkg.Svc/Get",
+			Code:    code,
+			Latency: 100 * time.Millisecond,
+		}
+		if err := w.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, err := Simulate(
+		&buf,
+		Policy{
+			Name:   "tight",
+			Global: grpcbreaker.Global(grpcbreaker.FailThreshold(3), grpcbreaker.ResetTimeout(5*time.Second)),
+		},
+		Policy{
+			Name:   "loose",
+			Global: grpcbreaker.Global(grpcbreaker.FailThreshold(100)),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Report{
+		{
+			// failures complete at 10.1s, 11.1s and 12.1s, tripping the breaker until 17.1s; the calls at 13s and 14s
+			// would have failed anyway, but those at 15s, 16s and 17s would have succeeded
+			Policy:         "tight",
+			Calls:          25,
+			Shed:           5,
+			ShedSuccesses:  3,
+			Trips:          1,
+			MeanTimeToTrip: 2 * time.Second,
+		},
+		{Policy: "loose", Calls: 25},
+	}
+	if !reflect.DeepEqual(reports, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, reports)
+	}
+}
+
+func TestSimulate_shedRecords(t *testing.T) {
+	// three failures trip the recording breaker, which sheds the next ten calls before the backend recovers
+	var buf bytes.Buffer
+	w := trace.NewWriter(&buf)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		rec := trace.Record{
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Method:  "/pkg.Svc/Get",
+			Latency: 100 * time.Millisecond,
+		}
+		switch {
+		case i < 3:
+			rec.Code = codes.Unavailable
+		case i < 13:
+			rec.Code, rec.Latency, rec.Shed = codes.Unknown, 0, true
+		}
+		if err := w.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, err := Simulate(&buf, Policy{Name: "loose", Global: grpcbreaker.Global(grpcbreaker.FailThreshold(5))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// were the shed calls replayed as failures, the breaker would trip
+	expected := []Report{{Policy: "loose", Calls: 20, Unreplayed: 10}}
+	if !reflect.DeepEqual(reports, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, reports)
+	}
+}
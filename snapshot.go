@@ -0,0 +1,181 @@
+package grpcbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoBreaker is returned when operating on a breaker which doesn't exist
+var ErrNoBreaker = errors.New("no such breaker")
+
+// Snapshot is the state of a single breaker at a moment
+type Snapshot struct {
+	Key
+	State                           GenState
+	Fails, Passes, Ignored          int
+	FailScore                       float64
+	LastFail, LastUsed, ResetMoment time.Time
+	// Causes are the most recent failures in the current state, oldest first
+	Causes []Cause
+	// Transitions are the breaker's most recent transitions, oldest first, as many as its HistorySize
+	Transitions []StateEvent
+}
+
+// Snapshot returns the state of every running breaker, ordered by Key
+func (b *Breaker) Snapshot() []Snapshot {
+	var snaps []Snapshot
+	b.cache.each(func(br *breaker) {
+		if s, ok := br.snapshot(); ok {
+			snaps = append(snaps, s)
+		}
+	})
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Key.less(snaps[j].Key) })
+	return snaps
+}
+
+// Get returns the state of the breaker with the given key; a Method or Service key only has a breaker of its own if
+// configured with an option set or created by KeyFunc
+func (b *Breaker) Get(key Key) (Snapshot, bool) {
+	br, ok := b.cache.find(key)
+	if !ok {
+		return Snapshot{}, false
+	}
+	return br.snapshot()
+}
+
+// Override forces the breaker with the given key into a state: Open sheds calls until the reset timeout as if it had
+// tripped, Closed clears its counters, and HalfOpen ends an Open breaker's reset timeout early
+func (b *Breaker) Override(key Key, state State) error {
+	br, ok := b.cache.find(key)
+	if !ok {
+		return fmt.Errorf("%v: %w", key, ErrNoBreaker)
+	}
+
+	for {
+		g := br.load()
+		switch {
+		case g == stopped:
+			return fmt.Errorf("%v: %w", key, ErrBreakerStopped)
+		case state == HalfOpen && g.State() != Open:
+			return fmt.Errorf("%v: only an Open breaker can be made HalfOpen but it's %v", key, g.State())
+		case state != Closed && state != HalfOpen && state != Open:
+			return fmt.Errorf("%v: invalid state %v", key, state)
+		}
+		if br.transition(g, state) {
+			return nil
+		}
+		// lost a race with a concurrent transition; try again from the new state
+	}
+}
+
+// Subscribe returns a channel receiving every event published after the call, in addition to Events, until ctx is
+// done; as with Events, events are dropped rather than blocking the breakers if the buffer is full
+func (b *Breaker) Subscribe(ctx context.Context, buffer int) <-chan Event {
+	return b.cache.global.subs.add(ctx, buffer, nil)
+}
+
+// SubscribeTransitions is Subscribe for only the StateEvents which are transitions, so that other events, e.g. the
+// sheds of an open breaker, can't crowd them out of the buffer
+func (b *Breaker) SubscribeTransitions(ctx context.Context, buffer int) <-chan Event {
+	return b.cache.global.subs.add(ctx, buffer, func(ev Event) bool {
+		e, ok := ev.(StateEvent)
+		return ok && e.Transition()
+	})
+}
+
+// Clock returns the clock used by the breakers, so that integrations can keep time consistently with them
+func (b *Breaker) Clock() Clock {
+	return b.cache.global.clock
+}
+
+func (br *breaker) snapshot() (Snapshot, bool) {
+	g := br.load()
+	if g == stopped {
+		return Snapshot{}, false
+	}
+	s := Snapshot{
+		Key:         br.Key,
+		State:       g.GenState,
+		Fails:       int(atomic.LoadInt64(&g.fails)),
+		Passes:      int(atomic.LoadInt64(&g.passes)),
+		Ignored:     int(atomic.LoadInt64(&g.ignored)),
+		FailScore:   float64(atomic.LoadInt64(&g.failScore)) / scoreScale,
+		LastFail:    br.lastFail(),
+		LastUsed:    br.lastUsed(),
+		ResetMoment: g.resetMoment,
+		Causes:      g.recentCauses(),
+	}
+	if br.history != nil {
+		s.Transitions = br.history.recentTransitions()
+	}
+	return s, true
+}
+
+// find returns the breaker with exactly the given key, not creating any
+func (bc *cache) find(key Key) (*breaker, bool) {
+	parent, ok := bc.load(Key{Type: key.Type, Name: key.Name})
+	switch {
+	case !ok:
+		return nil, false
+	case key.Partition == "":
+		return parent, parent.Key == key // otherwise the key's shared with a parent, e.g. a method with its service
+	case parent.partitions == nil || parent.Key.Partition != "":
+		return nil, false
+	}
+	return parent.partitions.lookup(key.Partition)
+}
+
+func (k Key) less(o Key) bool {
+	if k.Type != o.Type {
+		return k.Type < o.Type
+	}
+	if k.Name != o.Name {
+		return k.Name < o.Name
+	}
+	return k.Partition < o.Partition
+}
+
+// subscribers fans published events out to the channels returned by Subscribe
+type subscribers struct {
+	mu  sync.RWMutex
+	chs map[chan Event]func(Event) bool // to the filter of the subscription, nil for all events
+}
+
+func (s *subscribers) add(ctx context.Context, buffer int, filter func(Event) bool) <-chan Event {
+	ch := make(chan Event, buffer)
+
+	s.mu.Lock()
+	if s.chs == nil {
+		s.chs = make(map[chan Event]func(Event) bool)
+	}
+	s.chs[ch] = filter
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		delete(s.chs, ch)
+		close(ch) // safe, as publish sends while holding the read lock
+		s.mu.Unlock()
+	}()
+	return ch
+}
+
+func (s *subscribers) publish(ev Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch, filter := range s.chs {
+		if filter != nil && !filter(ev) {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
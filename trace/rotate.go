@@ -0,0 +1,66 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// RotatingWriter is an io.WriteCloser which starts a new underlying writer whenever the current one would exceed a
+// size limit. Each call to Write goes entirely to one underlying writer, so Records are never split between them.
+type RotatingWriter struct {
+	open     func(seq int) (io.WriteCloser, error)
+	maxBytes int64
+
+	cur  io.WriteCloser
+	seq  int
+	size int64
+}
+
+// NewRotatingWriter returns a RotatingWriter which calls open with sequence numbers 0, 1, 2... for each new writer,
+// rotating once a writer has been given maxBytes
+func NewRotatingWriter(open func(seq int) (io.WriteCloser, error), maxBytes int64) *RotatingWriter {
+	return &RotatingWriter{open: open, maxBytes: maxBytes}
+}
+
+// Files returns a function for use with NewRotatingWriter which creates files named by formatting the sequence number
+// into the pattern, e.g. "calls.%04d.jsonl"
+func Files(pattern string) func(seq int) (io.WriteCloser, error) {
+	return func(seq int) (io.WriteCloser, error) {
+		return os.Create(fmt.Sprintf(pattern, seq))
+	}
+}
+
+// Write writes p to the current writer, first rotating if p would take it over the limit
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	if w.cur != nil && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if err := w.cur.Close(); err != nil {
+			return 0, err
+		}
+		w.cur = nil
+		w.seq++
+	}
+
+	if w.cur == nil {
+		cur, err := w.open(w.seq)
+		if err != nil {
+			return 0, err
+		}
+		w.cur, w.size = cur, 0
+	}
+
+	n, err := w.cur.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current writer, if any; a subsequent Write starts a new one
+func (w *RotatingWriter) Close() error {
+	if w.cur == nil {
+		return nil
+	}
+	err := w.cur.Close()
+	w.cur = nil
+	w.seq++
+	return err
+}
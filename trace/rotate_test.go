@@ -0,0 +1,44 @@
+package trace
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestRotatingWriter(t *testing.T) {
+	var files []*bytes.Buffer
+	w := NewRotatingWriter(func(seq int) (io.WriteCloser, error) {
+		if seq != len(files) {
+			t.Fatalf("expected sequence %d but got %d", len(files), seq)
+		}
+		files = append(files, new(bytes.Buffer))
+		return nopCloser{files[seq]}, nil
+	}, 10)
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "a very long line\n", "dd\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, f := range files {
+		got = append(got, f.String())
+	}
+	// lines are never split, and an oversized line gets a file to itself
+	expected := []string{"aaaa\nbbbb\n", "cccc\n", "a very long line\n", "dd\n"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+}
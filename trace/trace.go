@@ -0,0 +1,99 @@
+// Package trace defines the JSONL format of recorded gRPC traffic, as written by grpcbreaker.Recorder and consumed by
+// the grpcbreaker policy simulator. Each line is a single JSON object describing one call:
+//
+//	{
+//	  "v": 1,                                  // the format version; see below
+//	  "time": "2021-01-01T00:00:00.5Z",        // RFC 3339 time at which the call started
+//	  "method": "/pkg.Service/Method",         // the full gRPC method
+//	  "call_site": "checkout",                 // the grpcbreaker.CallSite name, omitted if none
+//	  "code": 14,                              // the numeric gRPC status code with which the call completed
+//	  "latency_ns": 1200000,                   // how long the call took, in nanoseconds
+//	  "breaker": {                             // the breaker which handled the call, omitted if unknown
+//	    "type": "BreakerMethod",
+//	    "name": "/pkg.Service/Method",
+//	    "partition": "tenant-a"                // omitted if the breaker isn't a partition
+//	  },
+//	  "state": "Closed",                       // the breaker's state when the call started, omitted if unknown
+//	  "shed": true                             // whether the breaker rejected the call, omitted if false
+//	}
+//
+// Only time, method and code are required. The version is incremented whenever a change is made which older readers
+// would misinterpret; adding fields is not such a change, so consumers should ignore fields they don't know. A
+// missing version is treated as version 1.
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Version is the version of the format written by this package
+const Version = 1
+
+// Record is a single call
+type Record struct {
+	Version  int           `json:"v"`
+	Time     time.Time     `json:"time"`
+	Method   string        `json:"method"`
+	CallSite string        `json:"call_site,omitempty"`
+	Code     codes.Code    `json:"code"`
+	Latency  time.Duration `json:"latency_ns"`
+	Breaker  *Breaker      `json:"breaker,omitempty"`
+	State    string        `json:"state,omitempty"`
+	Shed     bool          `json:"shed,omitempty"`
+}
+
+// Breaker identifies a breaker; it mirrors grpcbreaker.Key
+type Breaker struct {
+	Type      string `json:"type"`
+	Name      string `json:"name,omitempty"`
+	Partition string `json:"partition,omitempty"`
+}
+
+// Reader reads Records from JSONL
+type Reader struct {
+	dec *json.Decoder
+}
+
+// NewReader returns a Reader reading from r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{json.NewDecoder(bufio.NewReader(r))}
+}
+
+// Read returns the next Record, or io.EOF when there are no more; it's an error to read a Record of a later version
+// than this package understands
+func (r *Reader) Read() (Record, error) {
+	var rec Record
+	if err := r.dec.Decode(&rec); err != nil {
+		return rec, err
+	}
+	if rec.Version == 0 {
+		rec.Version = 1
+	}
+	if rec.Version > Version {
+		return rec, fmt.Errorf("trace record has unsupported version %d", rec.Version)
+	}
+	return rec, nil
+}
+
+// Writer writes Records as JSONL
+type Writer struct {
+	enc *json.Encoder
+}
+
+// NewWriter returns a Writer writing to w. Each Record is written with a single call to w.Write, so w may rotate
+// between Records.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{json.NewEncoder(w)}
+}
+
+// Write writes a single Record as a line, setting its version
+func (w *Writer) Write(rec Record) error {
+	rec.Version = Version
+	return w.enc.Encode(rec)
+}
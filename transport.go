@@ -0,0 +1,152 @@
+package grpcbreaker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RoundTripper returns an http.RoundTripper protecting requests made with next, or http.DefaultTransport if nil.
+// Requests resolve to the breaker of the longest matching Path option set, else that of their Host option set, and
+// publish their events alongside those of gRPC calls. Never sharing the global breaker with gRPC calls, requests to
+// hosts without option sets get a Host breaker of their own configured by the Global options, which is evicted once
+// idle; beyond MaxHosts of these, requests to further hosts share a single Host breaker, with an empty name, until
+// idle ones are evicted.
+//
+// Transport errors fail the breaker, as do responses with a 5xx or 429 status; these responses are still returned to
+// the caller, but classifiers see them as a StatusError. Calls are identified to KeyFunc and classifiers by the
+// request method and URL, e.g. "GET api.example.com/v1/users", and KeyFunc is passed the *http.Request.
+func (b *Breaker) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &roundTripper{next: next, cache: b.cache}
+}
+
+type roundTripper struct {
+	next  http.RoundTripper
+	cache *cache
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := req.Method + " " + req.URL.Host + req.URL.Path
+	b := rt.cache.resolveHTTP(req.URL.Host, req.URL.Path).partition(req.Context(), method, req)
+
+	var (
+		resp    *http.Response
+		reached bool
+	)
+	err := b.call(req.Context(), method, func(ctx context.Context) (metadata.MD, error) {
+		reached = true
+		var err error
+		if resp, err = rt.next.RoundTrip(req); err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &StatusError{
+				StatusCode: resp.StatusCode,
+				Status:     resp.Status,
+				Retry:      retryAfter(resp.Header.Get("Retry-After"), b.clock.Now()),
+			}
+		}
+		return nil, nil
+	})
+	if !reached && req.Body != nil {
+		_ = req.Body.Close() // as RoundTrippers must, even on errors
+	}
+	if _, ok := err.(*StatusError); ok {
+		return resp, nil
+	}
+	return resp, err
+}
+
+// resolveHTTP returns the breaker of the longest Path which is a whole number of segments of the host and path, else
+// that of the Host, creating it if need be; if there are already too many hosts to create another, it returns the
+// breaker shared by the hosts beyond the max
+func (bc *cache) resolveHTTP(host, path string) *breaker {
+	for i := len(path); i > 0; i = strings.LastIndex(path, "/") {
+		path = path[:i]
+		if b, ok := bc.load(Key{Type: BreakerPath, Name: host + path}); ok {
+			return b
+		}
+	}
+	hostKey := Key{Type: BreakerHost, Name: host}
+	if b, ok := bc.load(hostKey); ok {
+		return b
+	}
+
+	if max := bc.global.maxHosts; max > 0 && atomic.LoadInt64(&bc.hosts) >= int64(max) {
+		// rather than grow without bound, share one breaker which, unlike the hosts', is never evicted
+		overflow := Key{Type: BreakerHost}
+		if b, ok := bc.load(overflow); ok {
+			return b
+		}
+		b, loaded := bc.loadOrStore(overflow, newBreaker(overflow, bc.global.deps, bc.global.settings))
+		if !loaded {
+			b.start()
+		}
+		return b
+	}
+
+	nb := newBreaker(hostKey, bc.global.deps, bc.global.settings)
+	nb.onIdle = func() {
+		bc.m.Delete(nb.Key)
+		atomic.AddInt64(&bc.hosts, -1)
+	}
+
+	b, loaded := bc.loadOrStore(hostKey, nb)
+	if !loaded {
+		atomic.AddInt64(&bc.hosts, 1)
+		b.start()
+	}
+	return b
+}
+
+// StatusError describes an HTTP response which failed the breaker
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Retry      time.Duration // from the Retry-After header, if any
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("http status %v", e.Status)
+}
+
+// RetryAfter returns the delay requested by the server, which the breaker respects should this response open it
+func (e *StatusError) RetryAfter() time.Duration {
+	return e.Retry
+}
+
+// GRPCStatus maps the response to a gRPC status as gRPC itself does for unexpected HTTP responses, so that it can be
+// classified by code
+func (e *StatusError) GRPCStatus() *status.Status {
+	code := codes.Unknown
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		code = codes.Unavailable
+	}
+	return status.New(code, e.Error())
+}
+
+// retryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
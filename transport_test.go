@@ -0,0 +1,121 @@
+package grpcbreaker_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwilner/grpcbreaker"
+	"github.com/jwilner/grpcbreaker/grpcbreakertest"
+)
+
+func TestBreaker_RoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/flaky/"):
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	closedAddr := func() string {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = lis.Close()
+		return lis.Addr().String()
+	}
+	deadHost, unconfiguredHost, overflowHost := closedAddr(), closedAddr(), closedAddr()
+
+	h := grpcbreakertest.NewBreaker(
+		t,
+		grpcbreaker.Global(grpcbreaker.FailThreshold(1), grpcbreaker.ResetTimeout(time.Second), grpcbreaker.MaxHosts(2)),
+		grpcbreaker.Path(host+"/flaky"),
+		grpcbreaker.Host(deadHost),
+	)
+	client := &http.Client{Transport: h.Breaker.RoundTripper(nil)}
+
+	get := func(url string) (int, error) {
+		t.Helper()
+		resp, err := client.Get(url)
+		if err != nil {
+			return 0, err
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	flakyKey := grpcbreaker.Key{Type: grpcbreaker.BreakerPath, Name: host + "/flaky"}
+	if code, err := get(srv.URL + "/flaky/1"); err != nil || code != http.StatusServiceUnavailable {
+		t.Fatalf("wanted a %v response but got %v, %v", http.StatusServiceUnavailable, code, err)
+	}
+	h.ExpectTransitions(flakyKey, grpcbreaker.Closed, grpcbreaker.Open)
+	body := &closeRecorder{Reader: strings.NewReader("{}")}
+	if _, err := client.Post(srv.URL+"/flaky/2", "application/json", body); !errors.Is(err, grpcbreaker.ErrBreakerOpen) {
+		t.Fatalf("wanted %v but got %v", grpcbreaker.ErrBreakerOpen, err)
+	}
+	if !body.closed {
+		t.Fatal("wanted the body of a shed request to be closed")
+	}
+
+	// the other paths of the host have a breaker of their own, untouched by client errors
+	for _, path := range []string{"/flakyish", "/missing"} {
+		if _, err := get(srv.URL + path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hostKey := grpcbreaker.Key{Type: grpcbreaker.BreakerHost, Name: host}
+	if snap, _ := h.Breaker.Get(hostKey); snap.State.State() != grpcbreaker.Closed {
+		t.Fatalf("wanted the host's breaker %v but got %v", grpcbreaker.Closed, snap.State)
+	}
+
+	// transport errors trip hosts' breakers, whether or not they're configured, but never the global breaker
+	for _, dead := range []string{deadHost, unconfiguredHost} {
+		if _, err := get("http://" + dead + "/"); err == nil {
+			t.Fatal("wanted a transport error")
+		}
+		h.ExpectTransitions(grpcbreaker.Key{Type: grpcbreaker.BreakerHost, Name: dead}, grpcbreaker.Closed, grpcbreaker.Open)
+	}
+	if snap, _ := h.Breaker.Get(grpcbreaker.Key{}); snap.State.State() != grpcbreaker.Closed {
+		t.Fatalf("wanted the global breaker %v but got %v", grpcbreaker.Closed, snap.State)
+	}
+
+	// the server's host and the unconfigured one reach MaxHosts, so further hosts share a breaker
+	if _, err := get("http://" + overflowHost + "/"); err == nil {
+		t.Fatal("wanted a transport error")
+	}
+	overflowKey := grpcbreaker.Key{Type: grpcbreaker.BreakerHost}
+	h.ExpectTransitions(overflowKey, grpcbreaker.Closed, grpcbreaker.Open)
+	if _, err := get("http://" + closedAddr() + "/"); !errors.Is(err, grpcbreaker.ErrBreakerOpen) {
+		t.Fatalf("wanted %v but got %v", grpcbreaker.ErrBreakerOpen, err)
+	}
+
+	// the reset timeout is extended to the Retry-After
+	h.Clock.Advance(time.Second)
+	if snap, _ := h.Breaker.Get(flakyKey); snap.State.State() != grpcbreaker.Open {
+		t.Fatalf("wanted %v but got %v", grpcbreaker.Open, snap.State)
+	}
+	h.Clock.Advance(29 * time.Second)
+	h.ExpectTransitions(flakyKey, grpcbreaker.Open, grpcbreaker.HalfOpen)
+}
+
+// closeRecorder is a request body recording whether it's been closed
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}